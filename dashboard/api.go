@@ -0,0 +1,483 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	database "github.com/rusinikita/devex/db"
+	"github.com/rusinikita/devex/filter"
+	"github.com/rusinikita/devex/project"
+)
+
+// apiDataset loads dashboard chart data for selected projects
+type apiDataset func(db *gorm.DB, params Params, projects []project.ID) (any, error)
+
+// registerAPI adds JSON routes for dashboard datasets.
+// Routes accept the same query params as dashboard page.
+func registerAPI(api *gin.RouterGroup) {
+	api.GET("/projects", func(ctx *gin.Context) {
+		var projects []project.Project
+		if err := database.GetDB(ctx).Find(&projects).Error; err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, projects)
+	})
+
+	api.GET("/changes/top", apiHandler(changesTopDataset))
+	api.GET("/changes/monthly", apiHandler(changesMonthlyDataset))
+	api.GET("/sizes", apiHandler(sizesDataset))
+	api.GET("/languages", apiHandler(languagesDataset))
+	api.GET("/declarations", apiHandler(declarationsDataset))
+	api.GET("/functions", apiHandler(functionsDataset))
+	api.GET("/contribution", apiHandler(contributionDataset))
+	api.GET("/commits", apiHandler(commitsDataset))
+	api.GET("/tags", apiHandler(tagsDataset))
+	api.GET("/imports", apiHandler(importsDataset))
+	api.GET("/architecture", apiHandler(architectureDataset))
+	api.GET("/stability", apiHandler(stabilityDataset))
+	api.GET("/hotspots", apiHandler(hotspotsDataset))
+	api.GET("/coverage", apiHandler(coverageDataset))
+	api.GET("/coverage/risky", apiHandler(riskyFilesDataset))
+	api.GET("/coverage/history", apiHandler(coverageHistoryDataset(false)))
+	api.GET("/coverage/history/packages", apiHandler(coverageHistoryDataset(true)))
+	api.GET("/coupling", apiHandler(couplingDataset))
+	api.GET("/knowledge", apiHandler(knowledgeDataset))
+	api.GET("/revisions", apiHandler(revisionsDataset))
+	api.GET("/revisions/sizes", apiHandler(revisionSizesDataset))
+	api.GET("/revisions/coverage", apiHandler(revisionDataset(revisionCoverage, true)))
+	api.GET("/revisions/lint", apiHandler(revisionDataset(revisionLintErrors, false)))
+	api.GET("/revisions/tags", apiHandler(revisionTagsDataset))
+}
+
+func apiHandler(dataset apiDataset) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		params := Params{}
+
+		if err := ctx.BindQuery(&params); err != nil {
+			return
+		}
+
+		db := database.GetDB(ctx)
+
+		var projects []project.Project
+		if err := db.Find(&projects).Error; err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		data, err := dataset(db, params, params.dataProjects(projects))
+		if err != nil {
+			ginErr := ctx.Error(err)
+			if errors.Is(err, errBadParams) {
+				ginErr.SetType(gin.ErrorTypePublic)
+			}
+
+			return
+		}
+
+		ctx.JSON(http.StatusOK, data)
+	}
+}
+
+// apiValues sets priorities and trims packages the same way as dashboard charts
+func apiValues(db *gorm.DB, params Params, projects []project.ID, data values) (values, error) {
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func changesTopDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := gitChangesTop(db, params.PerFiles, params.PerTeams, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiValues(db, params, projects, result)
+}
+
+func changesMonthlyDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	bars, err := gitChangesTop(db, params.PerFiles, params.PerTeams, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bars) > 20 {
+		bars = bars[:20]
+	}
+
+	result, err := gitChangesData(db, params.PerFiles, params.PerTeams, projects, bars)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiValues(db, params, projects, result)
+}
+
+func sizesDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := fileSizes(db, projects, params.sizeColumn(), filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiValues(db, params, projects, result)
+}
+
+func languagesDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := fileLanguages(db, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withShares(), nil
+}
+
+func declarationsDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := goDeclarations(db, params.PerFiles, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func functionsDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := complexFunctions(db, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func contributionDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := contribution(db, params.PerFiles, params.PerTeams, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiValues(db, params, projects, result)
+}
+
+func commitsDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	commitsFilter, err := params.commitsFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := commitMessages(db, params.PerFiles, params.PerTeams, projects, filesFilter, commitsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiValues(db, params, projects, result)
+}
+
+func tagsDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	tagsFilter, err := params.tagsFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := fileTags(db, projects, filesFilter, tagsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiValues(db, params, projects, result.tagsToValue(params.FileFilters))
+}
+
+func importsDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := imports(db, params.PerFilesImports, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func architectureDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := architectureViolations(db, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func stabilityDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := packageStability(db, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func hotspotsDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	fixesFilter, err := params.fixesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := fileHotspots(db, projects, params.sizeColumn(), params.hotspotMonths(), filesFilter, fixesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func coverageDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := fileCoverage(db, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func riskyFilesDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := riskyFiles(db, projects, params.hotspotMonths(), filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func coverageHistoryDataset(packagesMode bool) apiDataset {
+	return func(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+		filesFilter, err := params.filesFilter()
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := coverageHistory(db, packagesMode, projects, filesFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		priorities, err := packagePriorities(db, projects)
+		if err != nil {
+			return nil, err
+		}
+
+		return result.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+	}
+}
+
+func couplingDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := changeCoupling(db, params.PerFiles, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	fileImports, err := imports(db, params.PerFiles, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withImports(fileImports).withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func knowledgeDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := authorsKnowledge(db, params.PerFiles, projects, filesFilter, params.knowledgeMonths())
+	if err != nil {
+		return nil, err
+	}
+
+	priorities, err := packagePriorities(db, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.withPriorities(priorities).withPackagesTrimmed(strings.Split(params.TrimPackage, ",")), nil
+}
+
+func revisionsDataset(db *gorm.DB, _ Params, projects []project.ID) (any, error) {
+	return revisions(db, projects)
+}
+
+// errBadParams marks request params errors, they are returned with bad request status
+var errBadParams = errors.New("bad params")
+
+var (
+	errNoRevisions       = fmt.Errorf("%w: revision_from and revision_to are required", errBadParams)
+	errRevisionNotFound  = fmt.Errorf("%w: revision_from or revision_to is not found", errBadParams)
+	errRevisionsProjects = fmt.Errorf("%w: revision_from and revision_to must be revisions of the same project", errBadParams)
+	errCoverageRevision  = fmt.Errorf("%w: coverage revisions have coverage data only", errBadParams)
+)
+
+// revisionDataset returns revisions diff, coverage is true for coverage diff, the only one available for coverage revisions
+func revisionDataset(query func(db *gorm.DB, filesMode bool, from, to project.ID, filesFilter filter.SQL) (values, error), coverage bool) apiDataset {
+	return func(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+		if params.RevisionFrom == 0 || params.RevisionTo == 0 {
+			return nil, errNoRevisions
+		}
+
+		coverageOnly, err := checkRevisions(db, params.RevisionFrom, params.RevisionTo)
+		if err != nil {
+			return nil, err
+		}
+
+		if coverageOnly && !coverage {
+			return nil, errCoverageRevision
+		}
+
+		filesFilter, err := params.filesFilter()
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := query(db, params.PerFiles, params.RevisionFrom, params.RevisionTo, filesFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		return apiValues(db, params, projects, result)
+	}
+}
+
+func revisionSizesDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	return revisionDataset(func(db *gorm.DB, filesMode bool, from, to project.ID, filesFilter filter.SQL) (values, error) {
+		return revisionSizes(db, filesMode, params.sizeColumn(), from, to, filesFilter)
+	}, false)(db, params, projects)
+}
+
+func revisionTagsDataset(db *gorm.DB, params Params, projects []project.ID) (any, error) {
+	return revisionDataset(func(db *gorm.DB, filesMode bool, from, to project.ID, filesFilter filter.SQL) (values, error) {
+		result, err := revisionTags(db, filesMode, from, to, filesFilter)
+
+		return result.tagsDiff(params.FileFilters), err
+	}, false)(db, params, projects)
+}
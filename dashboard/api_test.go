@@ -0,0 +1,480 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusinikita/devex/db"
+	"github.com/rusinikita/devex/project"
+)
+
+func TestAPI(t *testing.T) {
+	database := db.TestDB("file:api?mode=memory&cache=shared")
+
+	require.NoError(t, database.Create(&project.Project{ID: 1, Alias: "api"}).Error)
+	require.NoError(t, database.Create(&project.Package{Project: 1, Path: "src/billing", Priority: project.Money}).Error)
+	require.NoError(t, database.Create([]project.File{
+		{
+			Project: 1, Package: "src/billing", Name: "pay.go", Language: "Go", Lines: 100, CodeLines: 80, Present: true,
+			PackageName: "billing", Functions: 4, ExportedFunctions: 2, ExportedTypes: 1, FunctionLines: 60, MaxFunctionLines: 30,
+		},
+		{
+			Project: 1, Package: "src/auth", Name: "login.go", Language: "Go", Lines: 50, CodeLines: 20, Present: true,
+			PackageName: "auth", Functions: 2, FunctionLines: 10, MaxFunctionLines: 6,
+		},
+		{Project: 1, Package: "src/auth", Name: "old.go", Language: "Go", Lines: 10},
+		{Project: 1, Package: "docs", Name: "readme.md", Language: "Markdown", Lines: 50, Present: true},
+	}).Error)
+
+	require.NoError(t, database.Create([]project.Function{
+		{File: 1, Name: "Pay", Line: 10, Lines: 30, Complexity: 3, Cognitive: 2},
+		{File: 2, Name: "Login", Line: 5, Lines: 40, Complexity: 8, Cognitive: 12},
+		{File: 3, Name: "Old", Line: 1, Lines: 100, Complexity: 50, Cognitive: 70},
+	}).Error)
+	require.NoError(t, database.Create([]project.GitCommit{
+		{ID: 1, Message: "fix login"},
+		{ID: 2, Message: "feature"},
+	}).Error)
+	require.NoError(t, database.Create([]project.GitChange{
+		{File: 1, Commit: 2, RowsAdded: 10, Time: time.Now()},
+		{File: 2, Commit: 1, RowsAdded: 30, RowsRemoved: 10, Time: time.Now()},
+		{File: 2, Commit: 2, RowsAdded: 20, Time: time.Now().AddDate(-2, 0, 0)},
+	}).Error)
+
+	require.NoError(t, database.Create([]project.Revision{
+		{ID: 1, Project: 1, CreatedAt: time.Now().AddDate(0, 0, -1)},
+		{ID: 2, Project: 1, Hash: "abc", CreatedAt: time.Now(), CoverageOnly: true},
+	}).Error)
+	require.NoError(t, database.Create([]project.Coverage{
+		{File: 1, Revision: 1, Percent: 90, UncoveredCount: 10},
+		{File: 1, Revision: 2, Percent: 80, UncoveredCount: 20},
+		{File: 2, Revision: 2, Percent: 100},
+		{File: 3, Revision: 2, Percent: 0, UncoveredCount: 10},
+	}).Error)
+
+	engine := newEngine(database)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+		return w
+	}
+
+	t.Run("sizes", func(t *testing.T) {
+		w := get("/api/v1/sizes?trim_package=src/&name_filter=.go")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result values
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.ElementsMatch(t, values{
+			{Alias: "api", Package: "billing", Name: "pay.go", Value: 100, Priority: project.Money},
+			{Alias: "api", Package: "auth", Name: "login.go", Value: 50},
+		}, result)
+	})
+
+	t.Run("code lines", func(t *testing.T) {
+		w := get("/api/v1/sizes?trim_package=src/&name_filter=.go&size_by=code_lines")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result values
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.ElementsMatch(t, values{
+			{Alias: "api", Package: "billing", Name: "pay.go", Value: 80, Priority: project.Money},
+			{Alias: "api", Package: "auth", Name: "login.go", Value: 20},
+		}, result)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		w := get("/api/v1/sizes?name_filter=~^[a-z]%2B\\.go$%3B!=login.go&package_filter=src/*&priority_filter=money")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result values
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		require.Len(t, result, 1)
+		assert.Equal(t, "pay.go", result[0].Name)
+	})
+
+	t.Run("filter syntax", func(t *testing.T) {
+		w := get("/api/v1/sizes?name_filter=~(")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("languages", func(t *testing.T) {
+		w := get("/api/v1/languages")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result languages
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.Equal(t, languages{
+			{Alias: "api", Language: "Go", Files: 2, Lines: 150, Share: 0.75},
+			{Alias: "api", Language: "Markdown", Files: 1, Lines: 50, Share: 0.25},
+		}, result)
+	})
+
+	t.Run("language filter", func(t *testing.T) {
+		w := get("/api/v1/sizes?language_filter=!=Go")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result values
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.Equal(t, values{{Alias: "api", Package: "docs", Name: "readme.md", Value: 50}}, result)
+	})
+
+	t.Run("declarations", func(t *testing.T) {
+		w := get("/api/v1/declarations?trim_package=src/")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result declarations
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.Equal(t, declarations{
+			{
+				Alias: "api", Package: "billing", PackageName: "billing", Files: 1, Functions: 4, ExportedFunctions: 2, ExportedTypes: 1,
+				FunctionLines: 60, MaxFunctionLines: 30, AvgFunctionLines: 15, Priority: project.Money,
+			},
+			{Alias: "api", Package: "auth", PackageName: "auth", Files: 1, Functions: 2, FunctionLines: 10, MaxFunctionLines: 6, AvgFunctionLines: 5},
+		}, result)
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		w := get("/api/v1/functions?trim_package=src/&name_filter=*.go")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result functions
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.Equal(t, functions{
+			{Alias: "api", Package: "auth", Name: "login.go", Function: "Login", Line: 5, Lines: 40, Complexity: 8, Cognitive: 12},
+			{Alias: "api", Package: "billing", Name: "pay.go", Function: "Pay", Line: 10, Lines: 30, Complexity: 3, Cognitive: 2, Priority: project.Money},
+		}, result)
+	})
+
+	t.Run("hotspots", func(t *testing.T) {
+		w := get("/api/v1/hotspots?trim_package=src/")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result hotspots
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.Equal(t, hotspots{
+			{Alias: "api", Package: "auth", Name: "login.go", Lines: 50, Changes: 40, Fixes: 1, Score: 50},
+			{Alias: "api", Package: "billing", Name: "pay.go", Lines: 100, Changes: 10, Score: 25, Priority: project.Money},
+		}, result)
+	})
+
+	t.Run("hotspots empty commit filters", func(t *testing.T) {
+		for _, filters := range []string{"%3B", ","} {
+			w := get("/api/v1/hotspots?commit_filters=" + filters)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var result hotspots
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+			require.Len(t, result, 2)
+			assert.Equal(t, float64(1), result[0].Fixes)
+		}
+	})
+
+	t.Run("hotspots code lines", func(t *testing.T) {
+		w := get("/api/v1/hotspots?size_by=code_lines")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result hotspots
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		require.Len(t, result, 2)
+		assert.ElementsMatch(t, []float64{20, 80}, []float64{result[0].Lines, result[1].Lines})
+	})
+
+	t.Run("coverage", func(t *testing.T) {
+		w := get("/api/v1/coverage?trim_package=src/")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result coverages
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.Equal(t, coverages{
+			{Alias: "api", Package: "billing", Name: "pay.go", Lines: 100, Percent: 80, Uncovered: 20, Priority: project.Money},
+			{Alias: "api", Package: "auth", Name: "login.go", Lines: 50, Percent: 100},
+		}, result)
+	})
+
+	t.Run("risky files", func(t *testing.T) {
+		w := get("/api/v1/coverage/risky?trim_package=src/")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result coverages
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.Equal(t, coverages{
+			{Alias: "api", Package: "billing", Name: "pay.go", Lines: 100, Percent: 80, Uncovered: 20, Changes: 10, Score: 200, Priority: project.Money},
+		}, result)
+	})
+
+	t.Run("coverage with priority and language filters", func(t *testing.T) {
+		for _, url := range []string{"/api/v1/coverage", "/api/v1/coverage/risky"} {
+			w := get(url + "?trim_package=src/&priority_filter=money&language_filter=Go")
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var result coverages
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			require.Len(t, result, 1, url)
+			assert.Equal(t, "pay.go", result[0].Name)
+		}
+	})
+
+	t.Run("page with priority and language filters", func(t *testing.T) {
+		w := get("/?priority_filter=money&language_filter=Go")
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	t.Run("coverage history", func(t *testing.T) {
+		w := get("/api/v1/coverage/history")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result coverageSnapshots
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		require.Len(t, result, 2)
+		assert.Equal(t, []float64{90, 81.3}, []float64{result[0].Percent, result[1].Percent})
+		assert.Equal(t, -8.7, result[1].Change)
+		assert.True(t, result[1].Regression)
+		assert.Equal(t, "abc", result[1].Hash)
+	})
+
+	t.Run("coverage history with priority and language filters", func(t *testing.T) {
+		for _, url := range []string{"/api/v1/coverage/history", "/api/v1/coverage/history/packages"} {
+			w := get(url + "?priority_filter=money&language_filter=Go")
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var result coverageSnapshots
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			require.Len(t, result, 2, url)
+			assert.Equal(t, []float64{90, 80}, []float64{result[0].Percent, result[1].Percent})
+		}
+	})
+
+	t.Run("packages coverage history", func(t *testing.T) {
+		w := get("/api/v1/coverage/history/packages?trim_package=src/")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result coverageSnapshots
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		regressions := result.regressions()
+		require.Len(t, regressions, 1)
+		assert.Equal(t, "billing", regressions[0].Package)
+		assert.Equal(t, float64(-10), regressions[0].Change)
+		assert.Equal(t, project.Money, regressions[0].Priority)
+	})
+
+	t.Run("knowledge", func(t *testing.T) {
+		w := get("/api/v1/knowledge?trim_package=src/")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result knowledges
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		assert.Equal(t, knowledges{
+			{Alias: "api", Package: "auth", Changes: 60, Authors: 1, TruckFactor: 1, MainAuthorShare: 1},
+			{Alias: "api", Package: "billing", Changes: 10, Authors: 1, TruckFactor: 1, MainAuthorShare: 1, Priority: project.Money},
+		}, result)
+	})
+
+	t.Run("revisions params", func(t *testing.T) {
+		w := get("/api/v1/revisions/sizes")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRevisionsAPI(t *testing.T) {
+	database := db.TestDB("file:revisions?mode=memory&cache=shared")
+
+	require.NoError(t, database.Create([]project.Project{{ID: 1, Alias: "r"}, {ID: 2, Alias: "other"}}).Error)
+	require.NoError(t, database.Create([]project.File{
+		{ID: 1, Project: 1, Package: "a", Name: "x.go", Lines: 10, Present: true},
+		{ID: 2, Project: 2, Package: "a", Name: "x.go", Present: true},
+		{ID: 3, Project: 1, Package: "a", Name: "y.go", Lines: 990, Present: true},
+	}).Error)
+	require.NoError(t, database.Create([]project.Revision{
+		{ID: 1, Project: 1, CreatedAt: time.Now().AddDate(0, 0, -1)},
+		{ID: 2, Project: 1, CreatedAt: time.Now()},
+		{ID: 3, Project: 2, CreatedAt: time.Now()},
+		{ID: 4, Project: 1, CreatedAt: time.Now(), CoverageOnly: true},
+		{ID: 5, Project: 1, CreatedAt: time.Now().AddDate(0, -1, 0), CoverageOnly: true},
+	}).Error)
+	require.NoError(t, database.Create([]project.Coverage{
+		{File: 1, Revision: 1, Percent: 50},
+		{File: 3, Revision: 1, Percent: 50},
+		{File: 1, Revision: 4, Percent: 90},
+		{File: 3, Revision: 4, Percent: 60},
+		{File: 1, Revision: 5, Percent: 10},
+	}).Error)
+	require.NoError(t, database.Create([]project.FileSnapshot{
+		{Revision: 1, File: 1, Lines: 10, CodeLines: 5},
+		{Revision: 2, File: 1, Lines: 30, CodeLines: 8},
+		{Revision: 3, File: 2, Lines: 100, CodeLines: 100},
+	}).Error)
+
+	engine := newEngine(database)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+		return w
+	}
+
+	value := func(url string) float64 {
+		w := get(url)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var result values
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.Len(t, result, 1)
+
+		return result[0].Value
+	}
+
+	assert.Equal(t, float64(20), value("/api/v1/revisions/sizes?revision_from=1&revision_to=2"))
+	assert.Equal(t, float64(3), value("/api/v1/revisions/sizes?revision_from=1&revision_to=2&size_by=code_lines"))
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/revisions/sizes?revision_from=1&revision_to=3").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/revisions/sizes?revision_from=1&revision_to=9").Code)
+
+	// coverage revisions have no files snapshots
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/revisions/sizes?revision_from=1&revision_to=4").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/revisions/tags?revision_from=1&revision_to=4").Code)
+
+	// coverage difference is weighted by file lines like coverage history
+	assert.InDelta(t, 10.3, value("/api/v1/revisions/coverage?revision_from=1&revision_to=4"), 0.01)
+
+	// back-filled snapshot is not the latest coverage
+	w := get("/api/v1/coverage")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var coverage coverages
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &coverage))
+	require.Len(t, coverage, 2)
+	assert.Equal(t, []uint8{90, 60}, []uint8{coverage[0].Percent, coverage[1].Percent})
+}
+
+func TestCouplingAPI(t *testing.T) {
+	database := db.TestDB("file:coupling?mode=memory&cache=shared")
+
+	require.NoError(t, database.Create(&project.Project{ID: 1, Alias: "c"}).Error)
+	require.NoError(t, database.Create([]project.File{
+		{ID: 1, Project: 1, Package: "a", Name: "x.go", Imports: []string{"github.com/m/b"}, Present: true},
+		{ID: 2, Project: 1, Package: "b", Name: "y.go", Present: true},
+		{ID: 3, Project: 1, Package: "c", Name: "z.go", Present: true},
+	}).Error)
+
+	for commit := project.ID(1); commit <= 4; commit++ {
+		require.NoError(t, database.Create(&project.GitCommit{ID: commit}).Error)
+
+		changes := []project.GitChange{
+			{File: 1, Commit: commit, Time: time.Now()},
+			{File: 2, Commit: commit, Time: time.Now()},
+		}
+		if commit < 4 {
+			changes = append(changes, project.GitChange{File: 3, Commit: commit, Time: time.Now()})
+		}
+
+		require.NoError(t, database.Create(changes).Error)
+	}
+
+	w := httptest.NewRecorder()
+	newEngine(database).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/coupling", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result couplings
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+	assert.Equal(t, couplings{
+		{Alias: "c", Package: "a", OtherPackage: "b", Shared: 4, Commits: 4, OtherCommits: 4, Total: 4, Support: 1, Confidence: 1, Degree: 100, Imported: true},
+		{Alias: "c", Package: "a", OtherPackage: "c", Shared: 3, Commits: 4, OtherCommits: 3, Total: 4, Support: 0.75, Confidence: 1, Degree: 86},
+		{Alias: "c", Package: "b", OtherPackage: "c", Shared: 3, Commits: 4, OtherCommits: 3, Total: 4, Support: 0.75, Confidence: 1, Degree: 86},
+	}, result)
+}
+
+func TestAuthorAliasesAPI(t *testing.T) {
+	database := db.TestDB("file:author_aliases?mode=memory&cache=shared")
+
+	require.NoError(t, database.Create(&project.Project{ID: 1, Alias: "a"}).Error)
+	require.NoError(t, database.Create(&project.File{ID: 1, Project: 1, Package: "pkg", Name: "a.go", Present: true}).Error)
+	require.NoError(t, database.Create([]project.GitCommit{{ID: 1, Author: "home@mail.com"}, {ID: 2, Author: "work@mail.com"}}).Error)
+	require.NoError(t, database.Create([]project.GitChange{
+		{File: 1, Commit: 1, RowsAdded: 200, Time: time.Now()},
+		{File: 1, Commit: 2, RowsAdded: 200, Time: time.Now()},
+	}).Error)
+	require.NoError(t, database.Create(&project.AuthorAlias{Author: "home@mail.com", Canonical: "work@mail.com"}).Error)
+
+	w := httptest.NewRecorder()
+	newEngine(database).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/contribution", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result values
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+	assert.Equal(t, values{{Alias: "a", Package: "pkg", Author: "work@mail.com", Value: 400}}, result)
+}
+
+func TestTeamsAPI(t *testing.T) {
+	database := db.TestDB("file:teams_api?mode=memory&cache=shared")
+
+	require.NoError(t, database.Create(&project.Project{ID: 1, Alias: "t"}).Error)
+	require.NoError(t, database.Create(&project.File{ID: 1, Project: 1, Package: "pkg", Name: "a.go", Present: true}).Error)
+	require.NoError(t, database.Create([]project.TeamMember{{Team: "backend", Author: "alice@mail.com"}}).Error)
+
+	for i := 1; i <= 8; i++ {
+		author := "alice@mail.com"
+		if i%2 == 0 {
+			author = "bob@mail.com"
+		}
+
+		require.NoError(t, database.Create(&project.GitCommit{ID: project.ID(i), Author: author, Message: "fix"}).Error)
+		require.NoError(t, database.Create(&project.GitChange{
+			File: 1, Commit: project.ID(i), RowsAdded: 100, Time: time.Now().AddDate(0, -i/2, 0),
+		}).Error)
+	}
+
+	engine := newEngine(database)
+
+	get := func(url string) (result values) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+
+		return result
+	}
+
+	assert.ElementsMatch(t, values{
+		{Alias: "t", Package: "pkg", Author: "backend", Value: 400},
+		{Alias: "t", Package: "pkg", Author: noTeam, Value: 400},
+	}, get("/api/v1/contribution?per_teams=true"))
+
+	assert.ElementsMatch(t, values{
+		{Alias: "t", Package: "pkg", Author: "backend", Value: 4},
+		{Alias: "t", Package: "pkg", Author: noTeam, Value: 4},
+	}, get("/api/v1/commits?per_teams=true&commit_filters=fix"))
+
+	top := get("/api/v1/changes/top?per_teams=true")
+	require.Len(t, top, 2)
+	assert.ElementsMatch(t, []string{"backend", noTeam}, []string{top[0].Author, top[1].Author})
+
+	monthly := get("/api/v1/changes/monthly?per_teams=true")
+	assert.Len(t, monthly, 8)
+}
@@ -0,0 +1,384 @@
+package dashboard
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"gorm.io/gorm"
+
+	"github.com/rusinikita/devex/filter"
+	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
+)
+
+const (
+	cycleViolation = "cycle"
+	ruleViolation  = "rule"
+)
+
+// violationData is packages import cycle or import rule violation
+type violationData struct {
+	Alias string `json:"alias"`
+	Kind  string `json:"kind"`
+	// Packages is imports chain, cycle ends with its first package
+	Packages []string `json:"packages"`
+	// File is the first chain package file with import
+	File string `json:"file"`
+	Rule string `json:"rule,omitempty"`
+}
+
+func (v violationData) imports() string {
+	return strings.Join(v.Packages, " -> ")
+}
+
+func (v violationData) String() string {
+	if v.Kind == cycleViolation {
+		return fmt.Sprintf("%s: import cycle %s (%s)", v.Alias, v.imports(), v.File)
+	}
+
+	return fmt.Sprintf("%s: %s, but %s (%s)", v.Alias, v.Rule, v.imports(), v.File)
+}
+
+type violations []violationData
+
+func (v violations) withPackagesTrimmed(prefixes []string) violations {
+	for i := range v {
+		v[i].Packages = slices.Map(v[i].Packages, func(p string) string {
+			return slices.MultiTrimPrefix(p, prefixes)
+		})
+		v[i].File = slices.MultiTrimPrefix(v[i].File, prefixes)
+	}
+
+	return v
+}
+
+func (v violations) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"Project", "Kind", "Imports", "Rule", "File"},
+	}
+
+	for _, data := range v {
+		t.Rows = append(t.Rows, []string{data.Alias, data.Kind, data.imports(), data.Rule, data.File})
+	}
+
+	return t
+}
+
+// importGraph is project packages imports, edge value is the first file with import
+type importGraph map[string]map[string]string
+
+// newImportGraph resolves files imports of one project to its packages, imports of the same package are skipped
+func newImportGraph(files allImports) importGraph {
+	// module or package path to package
+	modulePackages := map[string]string{}
+	for _, f := range files {
+		modulePackages[moduleName(f.Package, f.Name)] = f.Package
+	}
+
+	for _, f := range files {
+		modulePackages[f.Package] = f.Package
+	}
+
+	modules := make([]string, 0, len(modulePackages))
+	for m := range modulePackages {
+		modules = append(modules, m)
+	}
+
+	resolver := newModuleResolver(modules)
+
+	g := importGraph{}
+
+	for _, f := range files {
+		for _, imprt := range f.Imports {
+			if f.Language == "Go" && goStandardImport(imprt) {
+				continue
+			}
+
+			module, ok := resolver.resolve(imprt, false)
+			if !ok || modulePackages[module] == f.Package {
+				continue
+			}
+
+			if g[f.Package] == nil {
+				g[f.Package] = map[string]string{}
+			}
+
+			if _, ok := g[f.Package][modulePackages[module]]; !ok {
+				g[f.Package][modulePackages[module]] = path.Join(f.Package, f.Name)
+			}
+		}
+	}
+
+	return g
+}
+
+// goStandardImport reports whether import first path element has no dot, it is reserved for standard library
+func goStandardImport(imprt string) bool {
+	first, _, _ := strings.Cut(imprt, "/")
+
+	return !strings.Contains(first, ".")
+}
+
+func (g importGraph) packages() []string {
+	packages := make([]string, 0, len(g))
+	for p := range g {
+		packages = append(packages, p)
+	}
+
+	sort.Strings(packages)
+
+	return packages
+}
+
+func (g importGraph) imports(pkg string) []string {
+	imports := make([]string, 0, len(g[pkg]))
+	for p := range g[pkg] {
+		imports = append(imports, p)
+	}
+
+	sort.Strings(imports)
+
+	return imports
+}
+
+// cycles returns the shortest cycle of each strongly connected packages group found by Tarjan algorithm
+func (g importGraph) cycles() (cycles [][]string) {
+	index := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+
+	var stack []string
+
+	var connect func(pkg string)
+	connect = func(pkg string) {
+		index[pkg] = len(index)
+		low[pkg] = index[pkg]
+		stack = append(stack, pkg)
+		onStack[pkg] = true
+
+		for _, next := range g.imports(pkg) {
+			if _, visited := index[next]; !visited {
+				connect(next)
+
+				if low[next] < low[pkg] {
+					low[pkg] = low[next]
+				}
+			} else if onStack[next] && index[next] < low[pkg] {
+				low[pkg] = index[next]
+			}
+		}
+
+		if low[pkg] != index[pkg] {
+			return
+		}
+
+		group := slices.Set[string]{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			group[top] = true
+
+			if top == pkg {
+				break
+			}
+		}
+
+		if len(group) > 1 {
+			cycles = append(cycles, g.shortestCycle(group))
+		}
+	}
+
+	for _, pkg := range g.packages() {
+		if _, visited := index[pkg]; !visited {
+			connect(pkg)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+
+	return cycles
+}
+
+// shortestCycle returns the shortest cycle through the first group package, it is found by breadth first search
+func (g importGraph) shortestCycle(group slices.Set[string]) []string {
+	start := ""
+	for pkg := range group {
+		if start == "" || pkg < start {
+			start = pkg
+		}
+	}
+
+	parents := map[string]string{}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+
+		for _, next := range g.imports(pkg) {
+			if !group[next] {
+				continue
+			}
+
+			if next == start {
+				cycle := []string{start}
+				for p := pkg; p != start; p = parents[p] {
+					cycle = append(cycle, p)
+				}
+
+				cycle = append(cycle, start)
+				slices.Revert(cycle)
+
+				return cycle
+			}
+
+			if _, visited := parents[next]; !visited {
+				parents[next] = pkg
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+// violations returns import cycles and imports denied by rules
+func (g importGraph) violations(alias string, rules []project.ImportRule) (result violations) {
+	for _, cycle := range g.cycles() {
+		result = append(result, violationData{
+			Alias:    alias,
+			Kind:     cycleViolation,
+			Packages: cycle,
+			File:     g[cycle[0]][cycle[1]],
+		})
+	}
+
+	for _, from := range g.packages() {
+		for _, to := range g.imports(from) {
+			for _, rule := range rules {
+				glob := rule.Denied(from, to)
+				if glob == "" {
+					continue
+				}
+
+				result = append(result, violationData{
+					Alias:    alias,
+					Kind:     ruleViolation,
+					Packages: []string{from, to},
+					File:     g[from][to],
+					Rule:     fmt.Sprintf("%s must not import %s", rule.Package, glob),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// architectureViolations checks packages import graphs of projects
+func architectureViolations(db *gorm.DB, projects []project.ID, filesFilter filter.SQL) (result violations, err error) {
+	var projectsData []project.Project
+	if err = db.Order("id").Find(&projectsData, projects).Error; err != nil {
+		return nil, err
+	}
+
+	files, err := imports(db, true, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	graphs := files.graphs()
+
+	for _, p := range projectsData {
+		result = append(result, graphs[p.Alias].violations(p.Alias, p.Rules)...)
+	}
+
+	return result, nil
+}
+
+// CheckArchitecture returns project import cycles and import rules violations
+func CheckArchitecture(db *gorm.DB, alias string) ([]string, error) {
+	p := project.Project{}
+	if err := db.Take(&p, "alias = ?", alias).Error; err != nil {
+		return nil, err
+	}
+
+	result, err := architectureViolations(db, []project.ID{p.ID}, filter.SQL{})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Map(result, violationData.String), nil
+}
+
+const architectureChartID = "architecture"
+
+func architectureChart(data violations) (components.Charter, error) {
+	js, err := data.table("Import violations").js(architectureChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := slices.Map(data, func(v violationData) string {
+		return v.Alias
+	})
+	aliases = slices.Distinct(aliases)
+	sort.Strings(aliases)
+
+	counts := func(kind string) []opts.BarData {
+		return slices.Map(aliases, func(alias string) opts.BarData {
+			count := 0
+			for _, v := range data {
+				if v.Alias == alias && v.Kind == kind {
+					count++
+				}
+			}
+
+			return opts.BarData{Name: alias, Value: count}
+		})
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: architectureChartID,
+			Width:   "100%",
+			Height:  fmt.Sprintf("%dpx", 200+30*len(aliases)),
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Architecture",
+			Subtitle: "Packages import cycles and import rules violations, rules are set by 'devex architecture' command",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true}),
+		charts.WithLegendOpts(opts.Legend{Show: true}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Project",
+			Type: "category",
+			Show: true,
+			Data: aliases,
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Show: true,
+			Name: "Violations",
+			Type: "value",
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+	)
+
+	bar.AddSeries("Cycles", counts(cycleViolation), charts.WithBarChartOpts(opts.BarChart{Stack: "violations"}))
+	bar.AddSeries("Rules", counts(ruleViolation), charts.WithBarChartOpts(opts.BarChart{Stack: "violations"}))
+	bar.AddJSFuncs(js)
+
+	return bar, nil
+}
@@ -2,7 +2,6 @@ package dashboard
 
 import (
 	"fmt"
-	"path"
 
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/components"
@@ -14,13 +13,14 @@ import (
 func bar(name, desc string, data values) components.Charter {
 	slices.Revert(data)
 
-	names := data.barNames()
+	names := data.labels()
 
 	var barData []opts.BarData
 	for _, v := range data {
 		barData = append(barData, opts.BarData{
-			Name:  path.Join(v.Alias, v.Package, v.Name),
-			Value: v.Value,
+			Name:      v.label(),
+			Value:     v.Value,
+			ItemStyle: v.itemStyle(),
 		})
 	}
 
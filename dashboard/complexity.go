@@ -0,0 +1,158 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"path"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
+)
+
+// functionData is function complexity
+type functionData struct {
+	Alias      string           `json:"alias"`
+	Package    string           `json:"package"`
+	Name       string           `json:"name"`
+	Function   string           `json:"function"`
+	Line       uint32           `json:"line"`
+	Lines      uint32           `json:"lines"`
+	Complexity uint32           `json:"complexity"`
+	Cognitive  uint32           `json:"cognitive"`
+	Priority   project.Priority `json:"priority,omitempty" gorm:"-"`
+}
+
+func (d functionData) label() string {
+	return valueData{Alias: d.Alias, Package: d.Package, Name: d.Name, Priority: d.Priority}.label() + ":" + d.Function
+}
+
+type functions []functionData
+
+func (f functions) withPriorities(priorities map[string]project.Priority) functions {
+	for i := range f {
+		f[i].Priority = priorities[path.Join(f[i].Alias, f[i].Package)]
+	}
+
+	return f
+}
+
+func (f functions) withPackagesTrimmed(prefixes []string) functions {
+	for i := range f {
+		f[i].Package = slices.MultiTrimPrefix(f[i].Package, prefixes)
+	}
+
+	return f
+}
+
+func (f functions) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"Function", "Line", "Lines", "Cyclomatic complexity", "Cognitive complexity"},
+	}
+
+	for _, d := range f {
+		t.Rows = append(t.Rows, []string{
+			d.label(), fmt.Sprint(d.Line), fmt.Sprint(d.Lines), fmt.Sprint(d.Complexity), fmt.Sprint(d.Cognitive),
+		})
+	}
+
+	return t
+}
+
+const (
+	treemapColorComplexity = "complexity"
+	complexityChartID      = "complexity"
+)
+
+// withComplexityColors sets files colors by cognitive complexity share of the most complex file.
+// Square root makes colors of not the most complex files distinguishable.
+func (v values) withComplexityColors(complexities values) values {
+	maxComplexity := 0.0
+	colors := map[string]float64{}
+	for _, d := range complexities {
+		colors[path.Join(d.Alias, d.Package, d.Name)] = d.Value
+		maxComplexity = math.Max(maxComplexity, d.Value)
+	}
+
+	if maxComplexity == 0 {
+		return v
+	}
+
+	for i := range v {
+		v[i].Color = riskShareColor(math.Sqrt(colors[path.Join(v[i].Alias, v[i].Package, v[i].Name)] / maxComplexity))
+	}
+
+	return v
+}
+
+func complexityChart(data functions) (components.Charter, error) {
+	if len(data) > 30 {
+		data = data[:30]
+	}
+
+	js, err := data.table("The most complex functions").js(complexityChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// bar chart draws from bottom to top
+	reverted := append(functions{}, data...)
+	slices.Revert(reverted)
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: complexityChartID,
+			Width:   "100%",
+			Height:  fmt.Sprintf("%dpx", 200+20*len(reverted)),
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Complex functions",
+			Subtitle: "Go functions ordered by cyclomatic complexity",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true}),
+		charts.WithLegendOpts(opts.Legend{Show: true}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Function",
+			Type: "category",
+			Show: true,
+			Data: slices.Map(reverted, functionData.label),
+			AxisLabel: &opts.AxisLabel{
+				Show:         true,
+				ShowMinLabel: true,
+				ShowMaxLabel: true,
+			},
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Show: true,
+			Name: "Complexity",
+			Type: "value",
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	bar.AddSeries("Cyclomatic", slices.Map(reverted, func(d functionData) opts.BarData {
+		return opts.BarData{Name: d.label(), Value: d.Complexity}
+	}))
+	bar.AddSeries("Cognitive", slices.Map(reverted, func(d functionData) opts.BarData {
+		return opts.BarData{Name: d.label(), Value: d.Cognitive}
+	}))
+	bar.AddJSFuncs(js)
+
+	return bar, nil
+}
@@ -0,0 +1,289 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
+)
+
+// couplingData is pair of packages/files changed in the same commits
+type couplingData struct {
+	Alias        string  `json:"alias"`
+	Package      string  `json:"package"`
+	Name         string  `json:"name,omitempty"`
+	OtherPackage string  `json:"other_package"`
+	OtherName    string  `json:"other_name,omitempty"`
+	Shared       float64 `json:"shared"`        // commits with both changed
+	Commits      float64 `json:"commits"`       // commits with first changed
+	OtherCommits float64 `json:"other_commits"` // commits with second changed
+	Total        float64 `json:"total"`         // project commits in analysed period
+	// Support is part of all commits with both changed
+	Support float64 `json:"support" gorm:"-"`
+	// Confidence is max part of one side commits where other side is changed too
+	Confidence float64 `json:"confidence" gorm:"-"`
+	// Degree is shared commits percent of average commits count
+	Degree float64 `json:"degree" gorm:"-"`
+	// Imported is true if one side imports another, coupling without import is hidden
+	Imported      bool             `json:"imported" gorm:"-"`
+	Priority      project.Priority `json:"priority,omitempty" gorm:"-"`
+	OtherPriority project.Priority `json:"other_priority,omitempty" gorm:"-"`
+}
+
+func (d couplingData) first() valueData {
+	return valueData{Alias: d.Alias, Package: d.Package, Name: d.Name, Priority: d.Priority}
+}
+
+func (d couplingData) second() valueData {
+	return valueData{Alias: d.Alias, Package: d.OtherPackage, Name: d.OtherName, Priority: d.OtherPriority}
+}
+
+func (d couplingData) label() string {
+	return d.first().label() + " <-> " + d.second().label()
+}
+
+type couplings []couplingData
+
+func (c couplings) withMetrics() couplings {
+	round := func(f float64) float64 {
+		return math.Round(f*100) / 100
+	}
+
+	for i, d := range c {
+		if d.Total > 0 {
+			c[i].Support = round(d.Shared / d.Total)
+		}
+
+		if d.Commits > 0 && d.OtherCommits > 0 {
+			c[i].Confidence = round(math.Max(d.Shared/d.Commits, d.Shared/d.OtherCommits))
+			c[i].Degree = math.Round(100 * d.Shared / ((d.Commits + d.OtherCommits) / 2))
+		}
+	}
+
+	return c
+}
+
+// withImports marks pairs with import relationship, imports must not be trimmed
+func (c couplings) withImports(all allImports) couplings {
+	imports := map[string][]string{}
+	modules := map[string]map[string]string{}
+
+	for _, data := range all {
+		key := path.Join(data.Alias, data.Package, data.Name)
+		imports[key] = append(imports[key], data.Imports...)
+
+		if modules[data.Alias] == nil {
+			modules[data.Alias] = map[string]string{}
+		}
+
+		modules[data.Alias][data.Package] = data.Package
+		if data.Name != "" {
+			modules[data.Alias][moduleName(data.Package, data.Name)] = data.Package
+		}
+	}
+
+	resolvers := map[string]importResolver{}
+	for alias, m := range modules {
+		resolvers[alias] = newImportResolver(m)
+	}
+
+	for i, d := range c {
+		first, second := d.first(), d.second()
+		r := resolvers[d.Alias]
+
+		c[i].Imported = r.importsAny(imports[path.Join(first.Alias, first.Package, first.Name)], second) ||
+			r.importsAny(imports[path.Join(second.Alias, second.Package, second.Name)], first)
+	}
+
+	return c
+}
+
+// importResolver resolves imports to project modules, modules are packages and files without extension
+type importResolver struct {
+	modules moduleResolver
+	// packages is module package by module path
+	packages map[string]string
+}
+
+func newImportResolver(packages map[string]string) importResolver {
+	modules := make([]string, 0, len(packages))
+	for m := range packages {
+		modules = append(modules, m)
+	}
+
+	return importResolver{modules: newModuleResolver(modules), packages: packages}
+}
+
+// importsAny checks if any import is resolved to package or file module.
+// Import of file package points to the file too, file import points to its package.
+func (r importResolver) importsAny(imports []string, target valueData) bool {
+	for _, imprt := range imports {
+		module, ok := r.modules.resolve(imprt, target.Name == "")
+		if !ok {
+			continue
+		}
+
+		if target.Name == "" && r.packages[module] == target.Package {
+			return true
+		}
+
+		if target.Name != "" && (module == target.Package || module == moduleName(target.Package, target.Name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// importCandidates returns import path and its package entry modules
+func importCandidates(importPath string) []string {
+	return []string{importPath, path.Join(importPath, "index"), path.Join(importPath, "__init__")}
+}
+
+// importMatches checks if import points to module.
+// Go import path has module prefix, other languages import paths are relative to source root,
+// so module path can have source folder prefix.
+func importMatches(importPath, module string) bool {
+	return importPath == module ||
+		strings.HasSuffix(importPath, "/"+module) ||
+		strings.HasSuffix(module, "/"+importPath)
+}
+
+// importPaths returns import as path, python module import also points to its package
+func importPaths(imprt string) (paths []string, module bool) {
+	if strings.Contains(imprt, "/") || !strings.Contains(imprt, ".") {
+		return []string{imprt}, false
+	}
+
+	p := strings.ReplaceAll(imprt, ".", "/")
+
+	return []string{p, path.Dir(p)}, true
+}
+
+func (c couplings) withPriorities(priorities map[string]project.Priority) couplings {
+	for i := range c {
+		c[i].Priority = priorities[path.Join(c[i].Alias, c[i].Package)]
+		c[i].OtherPriority = priorities[path.Join(c[i].Alias, c[i].OtherPackage)]
+	}
+
+	return c
+}
+
+func (c couplings) withPackagesTrimmed(prefixes []string) couplings {
+	for i := range c {
+		c[i].Package = slices.MultiTrimPrefix(c[i].Package, prefixes)
+		c[i].OtherPackage = slices.MultiTrimPrefix(c[i].OtherPackage, prefixes)
+	}
+
+	return c
+}
+
+func (c couplings) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"Pair", "Shared commits", "Support", "Confidence", "Degree, %", "Imports"},
+	}
+
+	for _, d := range c {
+		imported := "hidden coupling"
+		if d.Imported {
+			imported = "yes"
+		}
+
+		t.Rows = append(t.Rows, []string{
+			d.label(), formatFloat(d.Shared), formatFloat(d.Support), formatFloat(d.Confidence), formatFloat(d.Degree), imported,
+		})
+	}
+
+	return t
+}
+
+const (
+	couplingChartID     = "coupling"
+	hiddenCouplingColor = "#d94e5d"
+	importCouplingColor = "#a7d8de"
+)
+
+func couplingChart(data couplings) (components.Charter, error) {
+	if len(data) > 30 {
+		data = data[:30]
+	}
+
+	js, err := data.table("Change coupling metrics").js(couplingChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// bar chart draws from bottom to top
+	reverted := append(couplings{}, data...)
+	slices.Revert(reverted)
+
+	names := slices.Map(reverted, couplingData.label)
+
+	barData := slices.Map(reverted, func(d couplingData) opts.BarData {
+		color := hiddenCouplingColor
+		if d.Imported {
+			color = importCouplingColor
+		}
+
+		return opts.BarData{
+			Name:      d.label(),
+			Value:     d.Degree,
+			ItemStyle: &opts.ItemStyle{Color: color},
+		}
+	})
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: couplingChartID,
+			Width:   "100%",
+			Height:  fmt.Sprintf("%dpx", 200+20*len(barData)),
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Change coupling",
+			Subtitle: "Packages/files changed together. Red - hidden coupling without imports between them",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Pair",
+			Type: "category",
+			Show: true,
+			Data: names,
+			AxisLabel: &opts.AxisLabel{
+				Show:         true,
+				ShowMinLabel: true,
+				ShowMaxLabel: true,
+			},
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Show: true,
+			Name: "Degree, %",
+			Type: "value",
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	bar.AddSeries("", barData)
+	bar.AddJSFuncs(js)
+
+	return bar, nil
+}
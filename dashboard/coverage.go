@@ -0,0 +1,349 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
+)
+
+// coverageData is file coverage of the latest coverage report, changes and score are set for risky files only
+type coverageData struct {
+	Alias     string `json:"alias"`
+	Package   string `json:"package"`
+	Name      string `json:"name"`
+	Lines     uint32 `json:"lines"`
+	Percent   uint8  `json:"percent"`
+	Uncovered uint32 `json:"uncovered"`
+	// Changes is file line changes, Score is line changes multiplied by uncovered lines
+	Changes  float64          `json:"changes,omitempty"`
+	Score    float64          `json:"score,omitempty"`
+	Priority project.Priority `json:"priority,omitempty" gorm:"-"`
+}
+
+func (d coverageData) label() string {
+	return valueData{Alias: d.Alias, Package: d.Package, Name: d.Name, Priority: d.Priority}.label()
+}
+
+type coverages []coverageData
+
+func (c coverages) withPriorities(priorities map[string]project.Priority) coverages {
+	for i := range c {
+		c[i].Priority = priorities[path.Join(c[i].Alias, c[i].Package)]
+	}
+
+	return c
+}
+
+func (c coverages) withPackagesTrimmed(prefixes []string) coverages {
+	for i := range c {
+		c[i].Package = slices.MultiTrimPrefix(c[i].Package, prefixes)
+	}
+
+	return c
+}
+
+// table returns risky files ranked in the same order
+func (c coverages) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"#", "File", "Score", "Line changes", "Uncovered lines", "Coverage"},
+	}
+
+	for i, d := range c {
+		t.Rows = append(t.Rows, []string{
+			strconv.Itoa(i + 1), d.label(), formatFloat(d.Score), formatFloat(d.Changes), fmt.Sprint(d.Uncovered), fmt.Sprintf("%d%%", d.Percent),
+		})
+	}
+
+	return t
+}
+
+const (
+	treemapColorCoverage = "coverage"
+	noCoverageColor      = "#bdbdbd"
+	riskyFilesChartID    = "risky_files"
+)
+
+// withCoverage sets coverage of files from the latest coverage report, it is shown in file size chart tooltip
+func (v values) withCoverage(files coverages) values {
+	percents := map[string]uint8{}
+	for _, d := range files {
+		percents[path.Join(d.Alias, d.Package, d.Name)] = d.Percent
+	}
+
+	for i := range v {
+		if percent, ok := percents[path.Join(v[i].Alias, v[i].Package, v[i].Name)]; ok {
+			v[i].Coverage = &percent
+		}
+	}
+
+	return v
+}
+
+// withCoverageColors sets files colors by uncovered lines share, files without coverage are grey
+func (v values) withCoverageColors() values {
+	for i := range v {
+		if v[i].Coverage == nil {
+			v[i].Color = noCoverageColor
+			continue
+		}
+
+		v[i].Color = riskShareColor(1 - float64(*v[i].Coverage)/100)
+	}
+
+	return v
+}
+
+// riskyFilesChart draws frequently changed files with uncovered lines, colour is coverage percent
+func riskyFilesChart(months int, data coverages) (components.Charter, error) {
+	rows := data
+	if len(rows) > 20 {
+		rows = rows[:20]
+	}
+
+	js, err := rows.table("Top risky files ordered by line changes multiplied by uncovered lines").js(riskyFilesChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	scatter := charts.NewScatter()
+	scatter.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: riskyFilesChartID,
+			Width:   "100%",
+			Height:  "600px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Risky files",
+			Subtitle: fmt.Sprintf("Files with uncovered lines and a lot of line changes in %d months. Colour is coverage percent", months),
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true, Formatter: "{b}<br/>changes, uncovered lines, coverage: {c}"}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Line changes",
+			Type: "value",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Uncovered lines",
+			Type: "value",
+		}),
+		charts.WithVisualMapOpts(opts.VisualMap{
+			Calculable: true,
+			Dimension:  "2",
+			Min:        0,
+			Max:        100,
+			InRange: &opts.VisualMapInRange{
+				Color: []string{"#d94e5d", "#eac736", "#a7d8de"},
+			},
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	scatter.AddSeries("Files", slices.Map(data, func(d coverageData) opts.ScatterData {
+		return opts.ScatterData{
+			Name:       d.label(),
+			Value:      []float64{d.Changes, float64(d.Uncovered), float64(d.Percent)},
+			SymbolSize: 12,
+		}
+	}))
+	scatter.AddJSFuncs(js)
+
+	return scatter, nil
+}
+
+// coverageSnapshotData is project or package coverage of revision, change is percent difference with previous snapshot
+type coverageSnapshotData struct {
+	Alias      string           `json:"alias"`
+	Package    string           `json:"package,omitempty"`
+	Revision   project.ID       `json:"revision"`
+	Hash       string           `json:"hash,omitempty"`
+	Time       time.Time        `json:"time"`
+	Percent    float64          `json:"percent"`
+	Uncovered  uint32           `json:"uncovered"`
+	Change     float64          `json:"change" gorm:"-"`
+	Regression bool             `json:"regression,omitempty" gorm:"-"`
+	Priority   project.Priority `json:"priority,omitempty" gorm:"-"`
+}
+
+func (d coverageSnapshotData) label() string {
+	return valueData{Alias: d.Alias, Package: d.Package, Priority: d.Priority}.label()
+}
+
+type coverageSnapshots []coverageSnapshotData
+
+// withChanges sets changes with previous snapshot of the same project or package, coverage decrease is regression.
+// Snapshots must be ordered by time.
+func (s coverageSnapshots) withChanges() coverageSnapshots {
+	previous := map[string]float64{}
+	for i, d := range s {
+		key := path.Join(d.Alias, d.Package)
+		if percent, ok := previous[key]; ok {
+			s[i].Change = math.Round((d.Percent-percent)*10) / 10
+			s[i].Regression = s[i].Change < 0
+		}
+
+		previous[key] = d.Percent
+	}
+
+	return s
+}
+
+func (s coverageSnapshots) withPriorities(priorities map[string]project.Priority) coverageSnapshots {
+	for i := range s {
+		s[i].Priority = priorities[path.Join(s[i].Alias, s[i].Package)]
+	}
+
+	return s
+}
+
+func (s coverageSnapshots) withPackagesTrimmed(prefixes []string) coverageSnapshots {
+	for i := range s {
+		s[i].Package = slices.MultiTrimPrefix(s[i].Package, prefixes)
+	}
+
+	return s
+}
+
+// regressions returns snapshots with coverage decrease, the newest and the biggest decrease first
+func (s coverageSnapshots) regressions() (result coverageSnapshots) {
+	for _, d := range s {
+		if d.Regression {
+			result = append(result, d)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Time.Equal(result[j].Time) {
+			return result[i].Time.After(result[j].Time)
+		}
+
+		return result[i].Change < result[j].Change
+	})
+
+	return result
+}
+
+func (s coverageSnapshots) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"Time", "Commit", "Package", "Coverage", "Change"},
+	}
+
+	for _, d := range s {
+		hash := d.Hash
+		if len(hash) > 8 {
+			hash = hash[:8]
+		}
+
+		t.Rows = append(t.Rows, []string{
+			d.Time.Format("2006-01-02 15:04"), hash, d.label(), formatFloat(d.Percent) + "%", formatFloat(d.Change),
+		})
+	}
+
+	return t
+}
+
+const coverageHistoryChartID = "coverage_history"
+
+// coverageHistoryChart draws projects coverage trend, regressions are marked by pins and packages regressions are listed in table
+func coverageHistoryChart(projects, packages coverageSnapshots) (components.Charter, error) {
+	rows := packages.regressions()
+	if len(rows) > 30 {
+		rows = rows[:30]
+	}
+
+	js, err := rows.table("Packages coverage regressions").js(coverageHistoryChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: coverageHistoryChartID,
+			Width:   "100%",
+			Height:  "500px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Coverage trend",
+			Subtitle: "Projects coverage of collections and 'devex coverage import' snapshots. Pins are coverage regressions",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true, Trigger: "axis"}),
+		charts.WithLegendOpts(opts.Legend{Show: true, Right: "10%"}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Time",
+			Type: "time",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Coverage, %",
+			Type: "value",
+			Max:  100,
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	aliases := slices.Distinct(slices.Map(projects, func(d coverageSnapshotData) string {
+		return d.Alias
+	}))
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		var points []opts.LineData
+		var pins []opts.MarkPointNameCoordItem
+
+		for _, d := range projects {
+			if d.Alias != alias {
+				continue
+			}
+
+			coordinate := []interface{}{d.Time.UnixMilli(), d.Percent}
+			points = append(points, opts.LineData{Name: d.Hash, Value: coordinate})
+
+			if d.Regression {
+				pins = append(pins, opts.MarkPointNameCoordItem{
+					Name:       "Regression",
+					Coordinate: coordinate,
+					Value:      formatFloat(d.Change),
+					ItemStyle:  &opts.ItemStyle{Color: riskColor},
+				})
+			}
+		}
+
+		line.AddSeries(alias, points, charts.WithMarkPointNameCoordItemOpts(pins...))
+	}
+
+	line.AddJSFuncs(js)
+
+	return line, nil
+}
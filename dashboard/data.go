@@ -1,6 +1,7 @@
 package dashboard
 
 import (
+	"math"
 	"path"
 	"path/filepath"
 	"sort"
@@ -9,21 +10,70 @@ import (
 	"github.com/go-echarts/go-echarts/v2/opts"
 	"github.com/lucasb-eyer/go-colorful"
 
+	"github.com/rusinikita/devex/filter"
+	"github.com/rusinikita/devex/project"
 	"github.com/rusinikita/devex/slices"
 )
 
 type valueData struct {
-	Alias   string
-	Package string
-	Name    string
-	Author  string
-	Time    string
-	Value   float64
-	Tags    map[string]uint32 `gorm:"serializer:json"`
+	Alias    string            `json:"alias"`
+	Package  string            `json:"package"`
+	Name     string            `json:"name,omitempty"`
+	Author   string            `json:"author,omitempty"`
+	Time     string            `json:"time,omitempty"`
+	Value    float64           `json:"value"`
+	Tags     map[string]uint32 `json:"tags,omitempty" gorm:"serializer:json"`
+	Priority project.Priority  `json:"priority,omitempty" gorm:"-"`
+	Color    string            `json:"-" gorm:"-"` // overrides priority color
+	// Coverage is covered lines percent of file with coverage report
+	Coverage *uint8 `json:"coverage,omitempty" gorm:"-"`
+}
+
+// label is package/file name with priority mark, author is team in teams mode
+func (d valueData) label() string {
+	name := filepath.Join(d.Alias, d.Package, d.Name)
+	if d.Author != "" {
+		name = d.Author + ": " + name
+	}
+
+	if d.Priority != project.Regular {
+		name += " [" + string(d.Priority) + "]"
+	}
+
+	return name
+}
+
+func (d valueData) itemStyle() *opts.ItemStyle {
+	if d.Color != "" {
+		return &opts.ItemStyle{Color: d.Color}
+	}
+
+	color, ok := priorityColors[d.Priority]
+	if !ok {
+		return nil
+	}
+
+	return &opts.ItemStyle{Color: color}
+}
+
+var priorityColors = map[project.Priority]string{
+	project.Vital:      "#4363d8",
+	project.Money:      "#f5b700",
+	project.Critical:   "#d94e5d",
+	project.Deprecated: "#9e9e9e",
 }
 
 type values []valueData
 
+// withPriorities sets packages priorities, it must be called before packages trimming
+func (v values) withPriorities(priorities map[string]project.Priority) values {
+	for i := range v {
+		v[i].Priority = priorities[path.Join(v[i].Alias, v[i].Package)]
+	}
+
+	return v
+}
+
 func (v values) withPackagesTrimmed(prefixes []string) values {
 	for i := range v {
 		v[i].Package = slices.MultiTrimPrefix(v[i].Package, prefixes)
@@ -34,10 +84,14 @@ func (v values) withPackagesTrimmed(prefixes []string) values {
 
 func (v values) barNames() []string {
 	return slices.Map(v, func(d valueData) string {
-		return filepath.Join(d.Alias, d.Package, d.Name)
+		return filepath.Join(d.Author, d.Alias, d.Package, d.Name)
 	})
 }
 
+func (v values) labels() []string {
+	return slices.Map(v, valueData.label)
+}
+
 func (v values) timeValues() (r []string) {
 	for _, d := range v {
 		r = append(r, d.Time)
@@ -51,9 +105,7 @@ func (v values) timeValues() (r []string) {
 
 func (v values) bar3dValues() (r [][3]any) {
 	return slices.Map(v, func(d valueData) [3]any {
-		nameFormat := filepath.Join(d.Alias, d.Package, d.Name)
-
-		return [3]any{d.Time, nameFormat, d.Value}
+		return [3]any{d.Time, d.label(), d.Value}
 	})
 }
 
@@ -67,7 +119,7 @@ func (v values) max() float64 {
 	})
 }
 
-func (v values) treeMaps() (result []opts.TreeMapNode) {
+func (v values) treeMaps() (result []treeMapNode) {
 	root := newFile("", 0)
 
 	for _, data := range v {
@@ -76,10 +128,10 @@ func (v values) treeMaps() (result []opts.TreeMapNode) {
 		root.insert(p, data.Name, int(data.Value))
 	}
 
-	return root.treeNode().Children
+	return root.childNodes()
 }
 
-func (v values) simpleMap() (result []opts.TreeMapNode) {
+func (v values) simpleMap() (result []treeMapNode) {
 	root := newFile("", 0)
 
 	for _, data := range v {
@@ -92,23 +144,31 @@ func (v values) simpleMap() (result []opts.TreeMapNode) {
 		folder, ok := project.children[data.Package]
 		if !ok {
 			folder = newFile(data.Package, 0)
+			folder.style = data.itemStyle()
 			project.children[data.Package] = folder
 		}
 
-		folder.children[data.Name] = newFile(data.Name, int(data.Value))
+		f := newFile(data.Name, int(data.Value))
+		f.coverage = data.Coverage
+		if data.Color != "" {
+			f.style = &opts.ItemStyle{Color: data.Color}
+		}
+
+		folder.children[data.Name] = f
 	}
 
-	return root.treeNode().Children
+	return root.childNodes()
 }
 
-func (v values) tagsToValue(tagsFilter string) (result values) {
-	tags := map[string]bool{}
+// tagsSet returns not negated tags of filter, invalid filter is validated before queries
+func tagsSet(tagsFilter string) map[string]bool {
+	expr, _ := filter.Parse(tagsFilter)
 
-	for _, split := range strings.Split(tagsFilter, ";") {
-		for _, s := range strings.Split(split, ",") {
-			tags[strings.TrimSuffix(s, "!")] = true
-		}
-	}
+	return slices.ToSet(expr.Values())
+}
+
+func (v values) tagsToValue(tagsFilter string) (result values) {
+	tags := tagsSet(tagsFilter)
 
 	for _, data := range v {
 		data := data
@@ -133,10 +193,66 @@ func (v values) tagsToValue(tagsFilter string) (result values) {
 	return result
 }
 
+// tagsDiff sums filtered tags counts of package/file snapshots.
+// Value sign of snapshot (1 or -1) is used as tags count multiplier.
+func (v values) tagsDiff(tagsFilter string) (result values) {
+	tags := tagsSet(tagsFilter)
+	index := map[string]int{}
+
+	for _, data := range v {
+		sign := data.Value
+		data.Value = 0
+
+		for tag, count := range data.Tags {
+			if tags[tag] {
+				data.Value += sign * float64(count)
+			}
+		}
+
+		key := filepath.Join(data.Alias, data.Package, data.Name)
+		if i, ok := index[key]; ok {
+			result[i].Value += data.Value
+			continue
+		}
+
+		data.Tags = nil
+		index[key] = len(result)
+		result = append(result, data)
+	}
+
+	changed := values{}
+	for _, data := range result {
+		if data.Value != 0 {
+			changed = append(changed, data)
+		}
+	}
+
+	sort.Slice(changed, func(i, j int) bool {
+		return math.Abs(changed[i].Value) > math.Abs(changed[j].Value)
+	})
+
+	if len(changed) > 40 {
+		return changed[:40]
+	}
+
+	return changed
+}
+
 type file struct {
 	name     string
 	children map[string]*file
 	value    int
+	style    *opts.ItemStyle
+	coverage *uint8
+}
+
+// treeMapNode is opts.TreeMapNode with item style and file coverage for tooltip
+type treeMapNode struct {
+	Name      string          `json:"name"`
+	Value     int             `json:"value,omitempty"`
+	ItemStyle *opts.ItemStyle `json:"itemStyle,omitempty"`
+	Coverage  *uint8          `json:"coverage,omitempty"`
+	Children  []treeMapNode   `json:"children,omitempty"`
 }
 
 func newFile(name string, value int) *file {
@@ -169,18 +285,32 @@ func (f *file) insert(filePath []string, name string, value int) {
 	}
 }
 
-func (f file) treeNode() opts.TreeMapNode {
-	node := opts.TreeMapNode{
-		Name:     f.name,
-		Value:    f.value,
-		Children: nil,
+// childNodes returns children tree nodes without collapsing root folder
+func (f file) childNodes() (nodes []treeMapNode) {
+	for _, ff := range f.children {
+		nodes = append(nodes, ff.treeNode())
+	}
+
+	return nodes
+}
+
+func (f file) treeNode() treeMapNode {
+	node := treeMapNode{
+		Name:      f.name,
+		Value:     f.value,
+		ItemStyle: f.style,
+		Coverage:  f.coverage,
+		Children:  nil,
 	}
 
 	for _, ff := range f.children {
-		if len(f.children) == 1 {
-			folder := node.Name
+		if len(f.children) == 1 && ff.children != nil {
+			folder, style := node.Name, node.ItemStyle
 			node = ff.treeNode()
 			node.Name = path.Join(folder, node.Name)
+			if node.ItemStyle == nil {
+				node.ItemStyle = style
+			}
 			break
 		}
 
@@ -191,11 +321,12 @@ func (f file) treeNode() opts.TreeMapNode {
 }
 
 type importsData struct {
-	Alias   string
-	Package string
-	Name    string
-	Lines   uint32
-	Imports []string `gorm:"serializer:json"`
+	Alias    string   `json:"alias"`
+	Package  string   `json:"package"`
+	Name     string   `json:"name,omitempty"`
+	Language string   `json:"language"`
+	Lines    uint32   `json:"lines"`
+	Imports  []string `json:"imports" gorm:"serializer:json"`
 }
 
 type allImports []importsData
@@ -212,10 +343,31 @@ func (all allImports) withPackagesTrimmed(prefixes []string) allImports {
 	return all
 }
 
+// graphs returns packages import graph of each project, files must not be trimmed
+func (all allImports) graphs() map[string]importGraph {
+	projectFiles := map[string]allImports{}
+	for _, data := range all {
+		projectFiles[data.Alias] = append(projectFiles[data.Alias], data)
+	}
+
+	graphs := map[string]importGraph{}
+	for alias, files := range projectFiles {
+		graphs[alias] = newImportGraph(files)
+	}
+
+	return graphs
+}
+
+// moduleName is file path without extension, imports point to it
+func moduleName(pkg, name string) string {
+	return path.Join(pkg, strings.TrimSuffix(name, path.Ext(name)))
+}
+
 func (all allImports) tree() (categories []*opts.GraphCategory, nodes []opts.GraphNode, links []opts.GraphLink) {
 	projectTrees := map[string]*file{}
 
 	maxLines := 0
+	packages := true
 
 	for _, data := range all {
 		project, ok := projectTrees[data.Alias]
@@ -224,7 +376,11 @@ func (all allImports) tree() (categories []*opts.GraphCategory, nodes []opts.Gra
 			projectTrees[data.Alias] = project
 		}
 
-		filePath := path.Join(data.Package, strings.TrimSuffix(data.Name, ".py"))
+		if data.Name != "" {
+			packages = false
+		}
+
+		filePath := moduleName(data.Package, data.Name)
 		f, ok := project.children[filePath]
 		if !ok {
 			f = newFile(filePath, 0)
@@ -239,10 +395,7 @@ func (all allImports) tree() (categories []*opts.GraphCategory, nodes []opts.Gra
 		}
 
 		for _, imprt := range data.Imports {
-			if !strings.Contains(imprt, "/") {
-				imprt = strings.ReplaceAll(imprt, ".", "/")
-			}
-			if len(imprt) < 3 || imprt == "" {
+			if len(imprt) < 3 {
 				continue
 			}
 
@@ -251,6 +404,13 @@ func (all allImports) tree() (categories []*opts.GraphCategory, nodes []opts.Gra
 	}
 
 	for alias, project := range projectTrees {
+		modules := make([]string, 0, len(project.children))
+		for fPath := range project.children {
+			modules = append(modules, fPath)
+		}
+
+		resolver := newModuleResolver(modules)
+
 		if alias == "push" {
 			alias = "_push_"
 		}
@@ -275,15 +435,18 @@ func (all allImports) tree() (categories []*opts.GraphCategory, nodes []opts.Gra
 
 			nodes = append(nodes, node)
 
+			targets := map[string]bool{}
 			for fileImport := range f.children {
-				_, hasImport := project.children[fileImport]
-				if !hasImport {
+				target, ok := resolver.resolve(fileImport, packages)
+				if !ok || target == fPath || targets[target] {
 					continue
 				}
 
+				targets[target] = true
+
 				link := opts.GraphLink{
 					Source: node.Name,
-					Target: path.Join(alias, fileImport),
+					Target: path.Join(alias, target),
 				}
 
 				links = append(links, link)
@@ -293,3 +456,65 @@ func (all allImports) tree() (categories []*opts.GraphCategory, nodes []opts.Gra
 
 	return categories, nodes, links
 }
+
+// moduleResolver finds project modules by import paths, modules are grouped by the last path element
+type moduleResolver map[string][]string
+
+func newModuleResolver(modules []string) moduleResolver {
+	r := moduleResolver{}
+	for _, m := range modules {
+		r[path.Base(m)] = append(r[path.Base(m)], m)
+	}
+
+	return r
+}
+
+// resolve returns project module matching import.
+// In packages mode module import points to its package.
+func (r moduleResolver) resolve(imprt string, packages bool) (string, bool) {
+	paths, _ := importPaths(imprt)
+
+	for _, p := range paths {
+		candidates := importCandidates(p)
+		if packages && strings.Contains(p, "/") {
+			candidates = append(candidates, path.Dir(p))
+		}
+
+		for _, candidate := range candidates {
+			if found, ok := r.match(candidate); ok {
+				return found, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// match returns module with the longest match of import path, the shortest module is taken from equal matches.
+// Import path matching several modules equally, e.g. "utils" of "a/utils" and "b/utils", is ambiguous and not resolved.
+func (r moduleResolver) match(importPath string) (string, bool) {
+	found, matched, ambiguous := "", 0, false
+
+	for _, m := range r[path.Base(importPath)] {
+		if !importMatches(importPath, m) {
+			continue
+		}
+
+		// import and module match by suffix of the shorter one
+		length := len(m)
+		if len(importPath) < length {
+			length = len(importPath)
+		}
+
+		depth, foundDepth := strings.Count(m, "/"), strings.Count(found, "/")
+
+		switch {
+		case found == "" || length > matched || (length == matched && depth < foundDepth):
+			found, matched, ambiguous = m, length, false
+		case length == matched && depth == foundDepth:
+			ambiguous = true
+		}
+	}
+
+	return found, found != "" && !ambiguous
+}
@@ -3,23 +3,24 @@ package dashboard
 import (
 	"testing"
 
+	"github.com/go-echarts/go-echarts/v2/opts"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/rusinikita/devex/db"
+	"github.com/rusinikita/devex/filter"
 	"github.com/rusinikita/devex/project"
 )
 
 func TestName2(t *testing.T) {
 	t.Skip("local test")
 
-	result, err := commitMessages(db.TestDB("../devex.db").Debug(), false, []project.ID{1}, "", "")
+	result, err := commitMessages(db.TestDB("../devex.db").Debug(), false, false, []project.ID{1}, filter.SQL{}, filter.SQL{})
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, result)
 }
 
-// todo failure test
-// Error:      	Should NOT be empty, but was []
 func TestTreemap(t *testing.T) {
 	v := values{
 		valueData{
@@ -34,3 +35,174 @@ func TestTreemap(t *testing.T) {
 	assert.NotEmpty(t, result)
 	assert.NotEmpty(t, result[0].Children)
 }
+
+func TestTreemapTooltip(t *testing.T) {
+	assert.Contains(t, toolTipFormatter("Code lines"), "' code lines'")
+	assert.Contains(t, toolTipFormatter("Lines"), "info.data.coverage + '%'")
+}
+
+func TestImportsAny(t *testing.T) {
+	r := newImportResolver(map[string]string{
+		"src/black":                          "src/black",
+		"src/black/nodes":                    "src/black",
+		"internal/db":                        "internal/db",
+		"internal/db/db":                     "internal/db",
+		"internal/db/sub":                    "internal/db/sub",
+		"src/app/components":                 "src/app/components",
+		"src/app/components/index":           "src/app/components",
+		"src/main/java/com/example/db":       "src/main/java/com/example/db",
+		"src/main/java/com/example/db/Store": "src/main/java/com/example/db",
+		"api/utils":                          "api/utils",
+		"web/utils":                          "web/utils",
+		"tests/data/src/black":               "tests/data/src/black",
+		"tests/data/src/black/nodes":         "tests/data/src/black",
+	})
+
+	assert.True(t, r.importsAny([]string{"black.nodes"}, valueData{Package: "src/black", Name: "nodes.py"}))
+	assert.True(t, r.importsAny([]string{"black.nodes"}, valueData{Package: "src/black"}))
+	assert.True(t, r.importsAny([]string{"github.com/m/internal/db"}, valueData{Package: "internal/db", Name: "db.go"}))
+	assert.False(t, r.importsAny([]string{"github.com/m/internal/db/sub"}, valueData{Package: "internal/db"}))
+	assert.False(t, r.importsAny([]string{"fmt"}, valueData{Package: ""}))
+	assert.True(t, r.importsAny([]string{"src/app/components"}, valueData{Package: "src/app/components", Name: "index.ts"}))
+	assert.True(t, r.importsAny([]string{"com/example/db/Store"}, valueData{Package: "src/main/java/com/example/db", Name: "Store.java"}))
+
+	// bare import matches several modules, so it is not resolved
+	assert.False(t, r.importsAny([]string{"utils"}, valueData{Package: "api/utils"}))
+	assert.True(t, r.importsAny([]string{"github.com/m/api/utils"}, valueData{Package: "api/utils"}))
+	assert.False(t, r.importsAny([]string{"github.com/m/api/utils"}, valueData{Package: "web/utils"}))
+}
+
+func TestImportsTree(t *testing.T) {
+	all := allImports{
+		{Alias: "p", Package: "src/app", Name: "main.ts", Lines: 10, Imports: []string{"react", "src/app/components", "src/app/api"}},
+		{Alias: "p", Package: "src/app/components", Name: "index.ts", Lines: 10},
+		{Alias: "p", Package: "src/app", Name: "api.js", Lines: 10, Imports: []string{"com/example/Store"}},
+		{Alias: "p", Package: "src/main/java/com/example", Name: "Store.java", Lines: 10},
+	}
+
+	_, nodes, links := all.tree()
+
+	assert.Len(t, nodes, 4)
+	assert.ElementsMatch(t, []opts.GraphLink{
+		{Source: "p/src/app/main", Target: "p/src/app/components/index"},
+		{Source: "p/src/app/main", Target: "p/src/app/api"},
+		{Source: "p/src/app/api", Target: "p/src/main/java/com/example/Store"},
+	}, links)
+}
+
+func TestKnowledge(t *testing.T) {
+	contributions := values{
+		{Alias: "p", Package: "a", Author: "old", Value: 60},
+		{Alias: "p", Package: "a", Author: "new", Value: 40},
+		{Alias: "p", Package: "b", Author: "old", Value: 30},
+		{Alias: "p", Package: "b", Author: "new", Value: 30},
+		{Alias: "p", Package: "b", Author: "third", Value: 40},
+	}
+	active := values{{Alias: "p", Author: "new"}, {Alias: "p", Author: "third"}}
+
+	assert.Equal(t, knowledges{
+		{Alias: "p", Package: "a", Changes: 100, Authors: 2, TruckFactor: 1, MainAuthor: "old", MainAuthorShare: 0.6, Orphaned: 0.6},
+		{Alias: "p", Package: "b", Changes: 100, Authors: 3, TruckFactor: 2, MainAuthor: "third", MainAuthorShare: 0.4, Orphaned: 0.3},
+	}, knowledge(contributions, active))
+
+	sizes := values{{Alias: "p", Package: "a"}, {Alias: "p", Package: "b"}}.
+		withKnowledgeColors(treemapColorTruckFactor, knowledge(contributions, active))
+
+	assert.Equal(t, riskColor, sizes[0].itemStyle().Color)
+	assert.Equal(t, warnColor, sizes[1].itemStyle().Color)
+}
+
+func TestComplexityColors(t *testing.T) {
+	sizes := values{
+		{Alias: "a", Package: "p", Name: "simple.go", Value: 100},
+		{Alias: "a", Package: "p", Name: "complex.go", Value: 100},
+		{Alias: "a", Package: "p", Name: "unknown.md", Value: 100},
+	}
+
+	colored := sizes.withComplexityColors(values{
+		{Alias: "a", Package: "p", Name: "simple.go", Value: 0},
+		{Alias: "a", Package: "p", Name: "complex.go", Value: 40},
+	})
+
+	assert.Equal(t, safeColor, colored[0].Color)
+	assert.Equal(t, riskColor, colored[1].Color)
+	assert.Equal(t, safeColor, colored[2].Color)
+}
+
+func TestCoverageColors(t *testing.T) {
+	sizes := values{
+		{Alias: "a", Package: "p", Name: "covered.go", Value: 100},
+		{Alias: "a", Package: "p", Name: "uncovered.go", Value: 100},
+		{Alias: "a", Package: "p", Name: "unknown.md", Value: 100},
+	}
+
+	colored := sizes.withCoverage(coverages{
+		{Alias: "a", Package: "p", Name: "covered.go", Percent: 100},
+		{Alias: "a", Package: "p", Name: "uncovered.go", Percent: 0},
+	}).withCoverageColors()
+
+	assert.Equal(t, safeColor, colored[0].Color)
+	assert.Equal(t, riskColor, colored[1].Color)
+	assert.Equal(t, noCoverageColor, colored[2].Color)
+	assert.Nil(t, colored[2].Coverage)
+
+	nodes := colored.simpleMap()
+	require.Len(t, nodes, 1)
+
+	coverage := map[string]*uint8{}
+	for _, n := range nodes[0].Children {
+		coverage[n.Name] = n.Coverage
+	}
+
+	assert.Equal(t, uint8(100), *coverage["covered.go"])
+	assert.Equal(t, uint8(0), *coverage["uncovered.go"])
+	assert.Nil(t, coverage["unknown.md"])
+}
+
+func TestArchitecture(t *testing.T) {
+	files := allImports{
+		{Package: "domain", Name: "user.go", Language: "Go", Imports: []string{"fmt", "github.com/m/infra/db", "github.com/other/lib"}},
+		{Package: "infra/db", Name: "db.go", Language: "Go", Imports: []string{"github.com/m/domain", "github.com/m/api"}},
+		{Package: "api", Name: "api.go", Language: "Go", Imports: []string{"github.com/m/domain"}},
+		{Package: "web", Name: "app.ts", Language: "TypeScript", Imports: []string{"web/util", "react"}},
+		{Package: "web/util", Name: "index.ts", Language: "TypeScript", Imports: []string{"web/app"}},
+	}
+
+	graph := newImportGraph(files)
+
+	assert.Equal(t, importGraph{
+		"domain":   {"infra/db": "domain/user.go"},
+		"infra/db": {"domain": "infra/db/db.go", "api": "infra/db/db.go"},
+		"api":      {"domain": "api/api.go"},
+		"web":      {"web/util": "web/app.ts"},
+		"web/util": {"web": "web/util/index.ts"},
+	}, graph)
+
+	assert.Equal(t, violations{
+		{Alias: "p", Kind: cycleViolation, Packages: []string{"api", "domain", "infra/db", "api"}, File: "api/api.go"},
+		{Alias: "p", Kind: cycleViolation, Packages: []string{"web", "web/util", "web"}, File: "web/app.ts"},
+		{Alias: "p", Kind: ruleViolation, Packages: []string{"domain", "infra/db"}, File: "domain/user.go", Rule: "domain must not import infra/*"},
+	}, graph.violations("p", []project.ImportRule{{Package: "domain", Deny: []string{"infra/*"}}}))
+}
+
+func TestStability(t *testing.T) {
+	all := allImports{
+		{Alias: "p", Package: "domain", Name: "user.go", Language: "Go", Lines: 100},
+		{Alias: "p", Package: "infra/db", Name: "db.go", Language: "Go", Lines: 50, Imports: []string{"github.com/m/domain"}},
+		{Alias: "p", Package: "api", Name: "api.go", Language: "Go", Lines: 10, Imports: []string{"github.com/m/domain", "github.com/m/infra/db"}},
+	}
+	decls := declarations{
+		{Alias: "p", Package: "domain", Types: 4, Interfaces: 1},
+		{Alias: "p", Package: "infra/db", Types: 2, Interfaces: 2},
+	}
+
+	assert.Equal(t, stabilities{
+		{Alias: "p", Package: "domain", Lines: 100, Afferent: 2, Instability: 0, Types: 4, Abstractness: 0.25, Distance: 0.75},
+		{Alias: "p", Package: "infra/db", Lines: 50, Afferent: 1, Efferent: 1, Instability: 0.5, Types: 2, Abstractness: 1, Distance: 0.5},
+		{Alias: "p", Package: "api", Lines: 10, Efferent: 2, Instability: 1},
+	}, stability(all, decls))
+
+	assert.Equal(t, "pain", stabilityData{Types: 4, Abstractness: 0.25, Distance: 0.75}.zone())
+	assert.Equal(t, "uselessness", stabilityData{Types: 2, Abstractness: 1, Instability: 0.5, Distance: 0.5}.zone())
+	assert.Empty(t, stabilityData{Efferent: 2, Instability: 1}.zone())
+}
@@ -2,45 +2,63 @@ package dashboard
 
 import (
 	"fmt"
+	"path"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
+	"github.com/rusinikita/devex/filter"
 	"github.com/rusinikita/devex/project"
 )
 
-func gitChangesTop(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter string) (result values, err error) {
+// gitChangesTop returns packages/files ordered by average line changes per month.
+// Teams mode splits packages/files changes by author teams.
+func gitChangesTop(db *gorm.DB, filesMode, teamsMode bool, projects []project.ID, filesFilter filter.SQL) (result values, err error) {
 	grouping := "alias, package"
-	barFilter := "alias || '/' || package"
+	presentFilter := ""
 
 	if filesMode {
 		grouping += ", name"
-		filesFilter = "and f.present > 0\n" + filesFilter
-		barFilter += " || '/' || name"
+		presentFilter = "and f.present > 0"
+	}
+
+	innerSelect, innerGroup, outerSelect, outerGroup := grouping, grouping, grouping, grouping
+	joins := ""
+
+	if teamsMode {
+		innerSelect = teamSQL + " as team, " + grouping
+		innerGroup = teamSQL + ", " + grouping
+		outerSelect = "team as author, " + grouping
+		outerGroup = "team, " + grouping
+		joins = teamJoins
 	}
 
 	sqlBars := `
-	with fcm as (select %[1]s, date("time", 'start of month') as month, sum(rows_added + rows_removed) as line_changes
+	with fcm as (select %[1]s, date(ch.time, 'start of month') as month, sum(rows_added + rows_removed) as line_changes
 		from git_changes as ch
 		join files f on ch.file = f.id
 		join projects p on f.project = p.id
+		%[6]s
 		where f.project in ?
 		   %[2]s
-		   and time > date('now', '-48 month')
-		group by %[1]s, date("time", 'start of month'))
-	select %[1]s, count(*), sum(line_changes), avg(line_changes) as value
-	from fcm group by %[1]s
+		   %[3]s
+		   and ch.time > date('now', '-48 month')
+		group by %[4]s, date(ch.time, 'start of month'))
+	select %[5]s, count(*), sum(line_changes), avg(line_changes) as value
+	from fcm group by %[7]s
 	having count(*) > 3
 	order by avg(line_changes) desc
 	limit 100
 `
-	sqlBars = fmt.Sprintf(sqlBars, grouping, filesFilter)
+	sqlBars = fmt.Sprintf(sqlBars, innerSelect, presentFilter, filesFilter.Prefixed().Query, innerGroup, outerSelect, joins, outerGroup)
 
-	err = db.Raw(sqlBars, projects).Scan(&result).Error
+	err = db.Raw(sqlBars, append([]any{projects}, filesFilter.Vars...)...).Scan(&result).Error
 
 	return result, err
 }
 
-func gitChangesData(db *gorm.DB, filesMode bool, projects []project.ID, bars values) (result values, err error) {
+func gitChangesData(db *gorm.DB, filesMode, teamsMode bool, projects []project.ID, bars values) (result values, err error) {
 	// Future: months/weeks selector
 
 	barStrings := bars.barNames()
@@ -53,47 +71,144 @@ func gitChangesData(db *gorm.DB, filesMode bool, projects []project.ID, bars val
 		barFilter += " || '/' || name"
 	}
 
+	selectColumns, joins := grouping, ""
+
+	if teamsMode {
+		selectColumns = teamSQL + " as author, " + grouping
+		grouping = teamSQL + ", " + grouping
+		barFilter = teamSQL + " || '/' || " + barFilter
+		joins = teamJoins
+	}
+
 	sql := `
-	select %[1]s, date("time", 'start of month') as 'time', sum(rows_added + rows_removed) as value
+	select %[1]s, date(ch.time, 'start of month') as 'time', sum(rows_added + rows_removed) as value
 	from git_changes as ch
 	join files f on ch.file = f.id
 	join projects p on f.project = p.id
+	%[4]s
 	where f.project in ?
 		and %[2]s in ?
-		and time > date('now', '-24 month')
-	group by %[1]s, date("time", 'start of month')
+		and ch.time > date('now', '-24 month')
+	group by %[3]s, date(ch.time, 'start of month')
 `
-	sql = fmt.Sprintf(sql, grouping, barFilter)
+	sql = fmt.Sprintf(sql, selectColumns, barFilter, grouping, joins)
 
 	err = db.Raw(sql, projects, barStrings).Scan(&result).Error
 
 	return result, err
 }
 
-func fileSizes(db *gorm.DB, projects []project.ID, filesFilter string) (result values, err error) {
-	err = db.Model(project.File{}).
-		Select("alias", "package", "name", "lines as value").
-		Joins("join projects p on p.id = files.project").
-		Where("present > 0 and project in ?"+filesFilter, projects).
+// fileSizes returns files size, column is "lines" or "code_lines"
+func fileSizes(db *gorm.DB, projects []project.ID, column string, filesFilter filter.SQL) (result values, err error) {
+	err = db.Table("files f").
+		Select("alias", "package", "name", column+" as value").
+		Joins("join projects p on p.id = f.project").
+		Where("present > 0 and project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Scan(&result).
+		Error
+
+	return result, err
+}
+
+// fileLanguages returns files and lines count per project language
+func fileLanguages(db *gorm.DB, projects []project.ID, filesFilter filter.SQL) (result languages, err error) {
+	err = db.Table("files f").
+		Select("alias", "f.language as language", "count(*) as files", "sum(lines) as lines").
+		Joins("join projects p on p.id = f.project").
+		Where("present > 0 and project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Group("alias, f.language").
+		Order("alias, lines desc, language").
+		Scan(&result).
+		Error
+
+	return result, err
+}
+
+// goDeclarations returns functions and types counts per package/file of parsed Go files
+func goDeclarations(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter filter.SQL) (result declarations, err error) {
+	grouping := "package"
+	if filesMode {
+		grouping += ", name"
+	}
+
+	err = db.Table("files f").
+		Select("alias", grouping,
+			"group_concat(distinct package_name) as package_name",
+			"count(*) as files",
+			"sum(functions) as functions",
+			"sum(exported_functions) as exported_functions",
+			"sum(exported_types) as exported_types",
+			"sum(types) as types",
+			"sum(interfaces) as interfaces",
+			"sum(function_lines) as function_lines",
+			"max(max_function_lines) as max_function_lines",
+		).
+		Joins("join projects p on p.id = f.project").
+		Where("present > 0 and package_name != '' and project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Group("alias, " + grouping).
+		Scan(&result).
+		Error
+
+	return result.withAverages(), err
+}
+
+// complexFunctions returns the most complex functions of present files
+func complexFunctions(db *gorm.DB, projects []project.ID, filesFilter filter.SQL) (result functions, err error) {
+	err = db.Model(project.Function{}).
+		Select("alias", "package", "f.name as name", "function_name as function",
+			"line", "functions.lines as lines", "functions.complexity as complexity", "cognitive").
+		Joins("join files f on f.id = functions.file").
+		Joins("join projects p on p.id = f.project").
+		Where("f.present > 0 and f.project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Order("functions.complexity desc, cognitive desc").
+		Limit(100).
 		Scan(&result).
 		Error
 
 	return result, err
 }
 
-func contribution(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter string) (result values, err error) {
+// authorSQL is commit author with aliases applied, query must join git_commits as 'c' and authorJoin
+const (
+	authorJoin = "left join author_aliases aa on aa.author = c.author"
+	authorSQL  = "coalesce(aa.canonical, c.author)"
+)
+
+// teamSQL is commit author team, query must join git_commits as 'c', authorJoin and teamJoin.
+// teamJoins joins all of them to git_changes as 'ch'.
+const (
+	teamJoin  = "left join team_members tm on tm.author = " + authorSQL
+	teamJoins = "join git_commits c on c.id = ch.'commit' " + authorJoin + " " + teamJoin
+	teamSQL   = "coalesce(tm.team, '" + noTeam + "')"
+	noTeam    = "no team"
+)
+
+// contribution returns authors line changes per package/file, teams mode returns teams instead of authors
+func contribution(db *gorm.DB, filesMode, teamsMode bool, projects []project.ID, filesFilter filter.SQL) (result values, err error) {
 	grouping := "package"
 	if filesMode {
 		grouping += ", name"
 	}
 
+	author := authorSQL
+	if teamsMode {
+		author = teamSQL
+	}
+
 	err = db.Model(project.GitChange{}).
-		Select("alias", grouping, "author", "sum(rows_added+rows_removed) as value").
+		Select("alias", grouping, author+" as author", "sum(rows_added+rows_removed) as value").
 		Joins("join git_commits c on c.id = git_changes.'commit'").
+		Joins(authorJoin).
+		Joins(teamJoin).
 		Joins("join files f on f.id = git_changes.file").
 		Joins("join projects p on p.id = f.project").
-		Where("git_changes.time > date('now', '-12 month') and f.project in ?"+filesFilter, projects).
-		Group("alias, author, " + grouping).
+		Where("git_changes.time > date('now', '-12 month') and f.project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Group("alias, " + author + ", " + grouping).
 		Having("sum(rows_added+rows_removed) > 300").
 		Scan(&result).
 		Error
@@ -103,18 +218,29 @@ func contribution(db *gorm.DB, filesMode bool, projects []project.ID, filesFilte
 
 // TODO contribution pace. velocity per month
 
-func commitMessages(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter, commitsFilter string) (result values, err error) {
+// commitMessages returns filtered commits count per package/file, teams mode splits them by author teams
+func commitMessages(db *gorm.DB, filesMode, teamsMode bool, projects []project.ID, filesFilter, commitsFilter filter.SQL) (result values, err error) {
 	grouping := "package"
 	if filesMode {
 		grouping += ", name"
 	}
 
+	selectColumns := "alias, " + grouping
+	if teamsMode {
+		selectColumns = teamSQL + " as author, " + selectColumns
+		grouping = teamSQL + ", " + grouping
+	}
+
 	err = db.Model(project.GitChange{}).
-		Select("alias", grouping, "count(*) as value").
+		Select(selectColumns, "count(*) as value").
 		Joins("join git_commits c on c.id = git_changes.'commit'").
+		Joins(authorJoin).
+		Joins(teamJoin).
 		Joins("join files f on f.id = git_changes.file").
 		Joins("join projects p on p.id = f.project").
-		Where("git_changes.time > date('now', '-24 month') and f.present > 0 and f.project in ?"+filesFilter+commitsFilter, projects).
+		Where("git_changes.time > date('now', '-24 month') and f.present > 0 and f.project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Where(commitsFilter.Query, commitsFilter.Vars...).
 		Group("alias, " + grouping).
 		Having("count(*) > 0").
 		Order("count(*) desc").
@@ -125,27 +251,429 @@ func commitMessages(db *gorm.DB, filesMode bool, projects []project.ID, filesFil
 	return result, err
 }
 
-func fileTags(db *gorm.DB, projects []project.ID, filesFilter, tagsFilter string) (result values, err error) {
-	err = db.Model(project.File{}).
+func fileTags(db *gorm.DB, projects []project.ID, filesFilter, tagsFilter filter.SQL) (result values, err error) {
+	err = db.Table("files f").
 		Select("alias", "package", "name", "tags").
-		Joins("join projects p on p.id = files.project").
-		Where("present > 0 and project in ?"+filesFilter+tagsFilter, projects).
+		Joins("join projects p on p.id = f.project").
+		Where("present > 0 and project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Where(tagsFilter.Query, tagsFilter.Vars...).
 		Scan(&result).Error
 
 	return result, err
 }
 
-func imports(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter string) (result allImports, err error) {
+func imports(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter filter.SQL) (result allImports, err error) {
 	grouping := "package"
 	if filesMode {
 		grouping += ", name"
 	}
 
-	err = db.Model(project.File{}).
-		Select("alias", grouping, "lines", "imports").
-		Joins("join projects p on p.id = files.project").
-		Find(&result, "present > 0 and project in ?"+filesFilter, projects).
+	err = db.Table("files f").
+		Select("alias", grouping, "f.language", "lines", "imports").
+		Joins("join projects p on p.id = f.project").
+		Where("present > 0 and project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Order(grouping).
+		Find(&result).
+		Error
+
+	return result, err
+}
+
+// fileHotspots returns files ordered by line changes and size product.
+// Fix commits are commits matching fixesFilter.
+func fileHotspots(db *gorm.DB, projects []project.ID, column string, months int, filesFilter, fixesFilter filter.SQL) (result hotspots, err error) {
+	sql := `
+	select alias, package, name, f.%[3]s as lines,
+		sum(ch.rows_added + ch.rows_removed) as changes,
+		count(distinct case when %[1]s then c.id end) as fixes
+	from git_changes ch
+	join git_commits c on c.id = ch.'commit'
+	join files f on f.id = ch.file
+	join projects p on p.id = f.project
+	where f.present > 0
+		and f.project in ?
+		and ch.time > date('now', ?)
+		%[2]s
+	group by f.id
+	order by sum(ch.rows_added + ch.rows_removed) * f.%[3]s desc
+	limit 100
+`
+	sql = fmt.Sprintf(sql, fixesFilter.Query, filesFilter.Prefixed().Query, column)
+
+	vars := append(append(append([]any{}, fixesFilter.Vars...), projects, fmt.Sprintf("-%d month", months)), filesFilter.Vars...)
+
+	err = db.Raw(sql, vars...).Scan(&result).Error
+
+	return result.withScores(), err
+}
+
+// latestCoverageSQL selects the latest by time revision with coverage report of each project,
+// back-filled coverage snapshots are older than the collected ones
+const latestCoverageSQL = `
+	latest as (
+		select project, revision
+		from (
+			select r.project, r.id as revision,
+				row_number() over (partition by r.project order by r.created_at desc, r.id desc) as n
+			from revisions r
+			where r.project in ? and exists (select 1 from coverages c where c.revision = r.id)
+		)
+		where n = 1
+	)`
+
+// fileCoverage returns files coverage of the latest project coverage report ordered by uncovered lines
+func fileCoverage(db *gorm.DB, projects []project.ID, filesFilter filter.SQL) (result coverages, err error) {
+	sql := `
+	with %[1]s
+	select alias, package, name, f.lines, c.percent, c.uncovered_count as uncovered
+	from coverages c
+	join latest l on l.revision = c.revision
+	join files f on f.id = c.file and f.project = l.project
+	join projects p on p.id = f.project
+	where f.present > 0
+		%[2]s
+	order by c.uncovered_count desc, alias, package, name
+`
+	sql = fmt.Sprintf(sql, latestCoverageSQL, filesFilter.Prefixed().Query)
+
+	err = db.Raw(sql, append([]any{projects}, filesFilter.Vars...)...).Scan(&result).Error
+
+	return result, err
+}
+
+// riskyFiles returns files with uncovered lines ordered by line changes in months multiplied by uncovered lines
+func riskyFiles(db *gorm.DB, projects []project.ID, months int, filesFilter filter.SQL) (result coverages, err error) {
+	sql := `
+	with %[1]s,
+	churn as (
+		select file, sum(rows_added + rows_removed) as changes
+		from git_changes
+		where time > date('now', ?)
+		group by file
+	)
+	select alias, package, name, f.lines, c.percent, c.uncovered_count as uncovered,
+		ch.changes, ch.changes * c.uncovered_count as score
+	from coverages c
+	join latest l on l.revision = c.revision
+	join files f on f.id = c.file and f.project = l.project
+	join churn ch on ch.file = f.id
+	join projects p on p.id = f.project
+	where f.present > 0
+		and c.uncovered_count > 0
+		%[2]s
+	order by score desc
+	limit 100
+`
+	sql = fmt.Sprintf(sql, latestCoverageSQL, filesFilter.Prefixed().Query)
+
+	vars := append([]any{projects, fmt.Sprintf("-%d month", months)}, filesFilter.Vars...)
+
+	err = db.Raw(sql, vars...).Scan(&result).Error
+
+	return result, err
+}
+
+// coveragePercentSQL returns files coverage weighted by file lines, it is average coverage if files have no lines.
+// Only coverages of revision are counted if revision is set, revision must be SQL value or placeholder.
+func coveragePercentSQL(revision string) string {
+	value := func(v string) string {
+		if revision == "" {
+			return v
+		}
+
+		return fmt.Sprintf("case when c.revision = %s then %s end", revision, v)
+	}
+
+	return fmt.Sprintf("coalesce(sum(%s) * 1.0 / nullif(sum(%s), 0), avg(%s))",
+		value("f.lines * c.percent"), value("f.lines"), value("c.percent"))
+}
+
+// coverageHistory returns projects or packages coverage of each revision with coverage ordered by time.
+// Percent is files coverage weighted by file lines.
+func coverageHistory(db *gorm.DB, packagesMode bool, projects []project.ID, filesFilter filter.SQL) (result coverageSnapshots, err error) {
+	grouping := "alias"
+	if packagesMode {
+		grouping += ", package"
+	}
+
+	sql := `
+	select %[1]s, r.id as revision, r.hash, r.created_at as time,
+		round(%[3]s, 1) as percent,
+		sum(c.uncovered_count) as uncovered
+	from coverages c
+	join revisions r on r.id = c.revision
+	join files f on f.id = c.file
+	join projects p on p.id = f.project
+	where f.project in ?
+		%[2]s
+	group by %[1]s, r.id
+	order by %[1]s, r.created_at, r.id
+`
+	sql = fmt.Sprintf(sql, grouping, filesFilter.Prefixed().Query, coveragePercentSQL(""))
+
+	err = db.Raw(sql, append([]any{projects}, filesFilter.Vars...)...).Scan(&result).Error
+
+	return result.withChanges(), err
+}
+
+const (
+	// couplingMaxCommitFiles skips mass changes like formatting or renaming, they are not coupling
+	couplingMaxCommitFiles = 30
+	couplingMinShared      = 3
+)
+
+// changeCoupling returns packages/files pairs changed in the same commits of last 24 months
+func changeCoupling(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter filter.SQL) (result couplings, err error) {
+	name := "''"
+	if filesMode {
+		name = "name"
+	}
+
+	sql := `
+	with changes as (
+		select distinct ch.'commit' as commit_id, f.project, alias, package, %[1]s as name
+		from git_changes ch
+		join files f on f.id = ch.file
+		join projects p on p.id = f.project
+		where f.present > 0
+			and f.project in ?
+			and ch.time > date('now', '-24 month')
+			and ch.'commit' in (select "commit" from git_changes group by "commit" having count(*) <= ?)
+			%[2]s
+	),
+	entities as (
+		select project, package, name, count(*) as commits
+		from changes
+		group by project, package, name
+	),
+	totals as (
+		select project, count(distinct commit_id) as total
+		from changes
+		group by project
+	)
+	select a.alias, a.package, a.name, b.package as other_package, b.name as other_name,
+		count(*) as shared, ea.commits as commits, eb.commits as other_commits, t.total as total
+	from changes a
+	join changes b on b.commit_id = a.commit_id and b.project = a.project
+		and (a.package < b.package or (a.package = b.package and a.name < b.name))
+	join entities ea on ea.project = a.project and ea.package = a.package and ea.name = a.name
+	join entities eb on eb.project = b.project and eb.package = b.package and eb.name = b.name
+	join totals t on t.project = a.project
+	group by a.project, a.package, a.name, b.package, b.name
+	having count(*) >= ?
+	order by count(*) * 1.0 / (ea.commits + eb.commits) desc, count(*) desc
+	limit 100
+`
+	sql = fmt.Sprintf(sql, name, filesFilter.Prefixed().Query)
+
+	vars := append(append([]any{projects, couplingMaxCommitFiles}, filesFilter.Vars...), couplingMinShared)
+
+	err = db.Raw(sql, vars...).Scan(&result).Error
+
+	return result.withMetrics(), err
+}
+
+// authorChanges returns authors line changes per package/file for all time
+func authorChanges(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter filter.SQL) (result values, err error) {
+	grouping := "alias, package"
+	if filesMode {
+		grouping += ", name"
+	}
+
+	err = db.Model(project.GitChange{}).
+		Select(grouping, authorSQL+" as author", "sum(rows_added+rows_removed) as value").
+		Joins("join git_commits c on c.id = git_changes.'commit'").
+		Joins(authorJoin).
+		Joins("join files f on f.id = git_changes.file").
+		Joins("join projects p on p.id = f.project").
+		Where("f.present > 0 and f.project in ?", projects).
+		Where(filesFilter.Query, filesFilter.Vars...).
+		Group(grouping + ", " + authorSQL).
+		Scan(&result).
+		Error
+
+	return result, err
+}
+
+// activeAuthors returns projects authors with commits in last months
+func activeAuthors(db *gorm.DB, projects []project.ID, months int) (result values, err error) {
+	err = db.Model(project.GitChange{}).
+		Distinct("alias", authorSQL+" as author").
+		Joins("join git_commits c on c.id = git_changes.'commit'").
+		Joins(authorJoin).
+		Joins("join files f on f.id = git_changes.file").
+		Joins("join projects p on p.id = f.project").
+		Where("f.project in ? and git_changes.time > date('now', ?)", projects, fmt.Sprintf("-%d month", months)).
+		Scan(&result).
+		Error
+
+	return result, err
+}
+
+type revisionData struct {
+	ID        project.ID
+	Alias     string
+	Hash      string
+	CreatedAt time.Time
+	// CoverageOnly revision has imported coverage only
+	CoverageOnly bool
+}
+
+func (r revisionData) Title() string {
+	hash := r.Hash
+	if len(hash) > 8 {
+		hash = hash[:8]
+	}
+
+	if r.CoverageOnly {
+		hash += " coverage"
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", r.Alias, r.CreatedAt.Format("2006-01-02 15:04"), hash))
+}
+
+func revisions(db *gorm.DB, projects []project.ID) (result []revisionData, err error) {
+	err = db.Model(project.Revision{}).
+		Select("revisions.id", "alias", "hash", "revisions.created_at", "coverage_only").
+		Joins("join projects p on p.id = revisions.project").
+		Where("revisions.project in ?", projects).
+		Order("revisions.created_at desc").
+		Scan(&result).
 		Error
 
 	return result, err
 }
+
+// checkRevisions returns error if revisions are not found or belong to different projects.
+// coverageOnly is true if one of revisions is coverage snapshot without files data.
+func checkRevisions(db *gorm.DB, from, to project.ID) (coverageOnly bool, err error) {
+	var result []project.Revision
+
+	err = db.Select("id", "project", "coverage_only").Find(&result, []project.ID{from, to}).Error
+	if err != nil {
+		return false, err
+	}
+
+	expected := 2
+	if from == to {
+		expected = 1
+	}
+
+	if len(result) != expected {
+		return false, errRevisionNotFound
+	}
+
+	if result[0].Project != result[len(result)-1].Project {
+		return false, errRevisionsProjects
+	}
+
+	for _, r := range result {
+		coverageOnly = coverageOnly || r.CoverageOnly
+	}
+
+	return coverageOnly, nil
+}
+
+// revisionsDiff returns newer revision value minus older revision value for each package/file.
+// fromSQL must join files table as 'f', revisionColumn is revision column of joined data table.
+func revisionsDiff(db *gorm.DB, filesMode bool, from, to project.ID, value filter.SQL, fromSQL, revisionColumn string, filesFilter filter.SQL) (result values, err error) {
+	grouping := "alias, package"
+	if filesMode {
+		grouping += ", name"
+	}
+
+	sql := `
+	select %[1]s, %[2]s as value
+	%[3]s
+	join projects p on p.id = f.project
+	where %[4]s in (?, ?)
+		%[5]s
+	group by %[1]s
+	having value != 0
+	order by abs(value) desc
+	limit 40
+`
+	sql = fmt.Sprintf(sql, grouping, value.Query, fromSQL, revisionColumn, filesFilter.Prefixed().Query)
+
+	vars := append(append(append([]any{}, value.Vars...), from, to), filesFilter.Vars...)
+
+	err = db.Raw(sql, vars...).Scan(&result).Error
+
+	return result, err
+}
+
+// revisionSizes returns files size difference, column is "lines" or "code_lines"
+func revisionSizes(db *gorm.DB, filesMode bool, column string, from, to project.ID, filesFilter filter.SQL) (values, error) {
+	return revisionsDiff(db, filesMode, from, to,
+		filter.SQL{Query: fmt.Sprintf("sum(case when s.revision = ? then s.%[1]s else -s.%[1]s end)", column), Vars: []any{to}},
+		"from file_snapshots s join files f on f.id = s.file",
+		"s.revision", filesFilter)
+}
+
+// revisionCoverage returns coverage difference weighted by file lines, the same as coverage history
+func revisionCoverage(db *gorm.DB, filesMode bool, from, to project.ID, filesFilter filter.SQL) (values, error) {
+	return revisionsDiff(db, filesMode, from, to,
+		filter.SQL{
+			Query: coveragePercentSQL("?") + " - " + coveragePercentSQL("?"),
+			Vars:  []any{to, to, to, from, from, from},
+		},
+		"from coverages c join files f on f.id = c.file",
+		"c.revision", filesFilter)
+}
+
+func revisionLintErrors(db *gorm.DB, filesMode bool, from, to project.ID, filesFilter filter.SQL) (values, error) {
+	return revisionsDiff(db, filesMode, from, to,
+		filter.SQL{Query: "sum(case when l.revision = ? then 1 else -1 end)", Vars: []any{to}},
+		"from lint_errors l join files f on f.id = l.file_id",
+		"l.revision", filesFilter)
+}
+
+// revisionTags returns file snapshots tags, value is 1 for newer revision and -1 for older one
+func revisionTags(db *gorm.DB, filesMode bool, from, to project.ID, filesFilter filter.SQL) (result values, err error) {
+	grouping := "alias, package"
+	if filesMode {
+		grouping += ", name"
+	}
+
+	sql := `
+	select %[1]s, s.tags, case when s.revision = ? then 1 else -1 end as value
+	from file_snapshots s
+	join files f on f.id = s.file
+	join projects p on p.id = f.project
+	where s.revision in (?, ?)
+		%[2]s
+`
+	sql = fmt.Sprintf(sql, grouping, filesFilter.Prefixed().Query)
+
+	err = db.Raw(sql, append([]any{to, from, to}, filesFilter.Vars...)...).Scan(&result).Error
+
+	return result, err
+}
+
+// packagePriorities returns not regular priorities by 'alias/package' key
+func packagePriorities(db *gorm.DB, projects []project.ID) (map[string]project.Priority, error) {
+	var result []struct {
+		Alias    string
+		Path     string
+		Priority project.Priority
+	}
+
+	err := db.Model(project.Package{}).
+		Select("alias", "path", "priority").
+		Joins("join projects p on p.id = packages.project").
+		Where("project in ? and priority != ''", projects).
+		Scan(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	priorities := map[string]project.Priority{}
+	for _, r := range result {
+		priorities[path.Join(r.Alias, r.Path)] = r.Priority
+	}
+
+	return priorities, nil
+}
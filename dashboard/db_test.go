@@ -7,13 +7,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/rusinikita/devex/db"
+	"github.com/rusinikita/devex/filter"
 	"github.com/rusinikita/devex/project"
 )
 
 func Test_fileSizes(t *testing.T) {
 	database := db.TestDB("../devex.db")
 
-	gotResult, err := fileSizes(database, []project.ID{1}, "and (name like '%.go' or name like '%.md')")
+	expr, err := filter.Parse("*.go,*.md")
+	require.NoError(t, err)
+
+	gotResult, err := fileSizes(database, []project.ID{1}, "lines", expr.SQL("name"))
 	require.NoError(t, err)
 
 	fmt.Println(gotResult.barNames())
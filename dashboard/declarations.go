@@ -0,0 +1,161 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
+)
+
+// declarationsData is Go package/file functions and types summary
+type declarationsData struct {
+	Alias   string `json:"alias"`
+	Package string `json:"package"`
+	Name    string `json:"name,omitempty"`
+	// PackageName is declared package names, files of one folder can declare test package too
+	PackageName       string  `json:"package_name"`
+	Files             float64 `json:"files"`
+	Functions         float64 `json:"functions"`
+	ExportedFunctions float64 `json:"exported_functions"`
+	ExportedTypes     float64 `json:"exported_types"`
+	Types             float64 `json:"types"`
+	Interfaces        float64 `json:"interfaces"`
+	FunctionLines     float64 `json:"function_lines"`
+	MaxFunctionLines  float64 `json:"max_function_lines"`
+	// AvgFunctionLines is average function length
+	AvgFunctionLines float64          `json:"avg_function_lines" gorm:"-"`
+	Priority         project.Priority `json:"priority,omitempty" gorm:"-"`
+}
+
+func (d declarationsData) label() string {
+	return valueData{Alias: d.Alias, Package: d.Package, Name: d.Name, Priority: d.Priority}.label()
+}
+
+type declarations []declarationsData
+
+// withAverages sets average function length and orders by it
+func (d declarations) withAverages() declarations {
+	for i, data := range d {
+		if data.Functions > 0 {
+			d[i].AvgFunctionLines = math.Round(10*data.FunctionLines/data.Functions) / 10
+		}
+	}
+
+	sort.SliceStable(d, func(i, j int) bool {
+		return d[i].AvgFunctionLines > d[j].AvgFunctionLines
+	})
+
+	return d
+}
+
+func (d declarations) withPriorities(priorities map[string]project.Priority) declarations {
+	for i := range d {
+		d[i].Priority = priorities[path.Join(d[i].Alias, d[i].Package)]
+	}
+
+	return d
+}
+
+func (d declarations) withPackagesTrimmed(prefixes []string) declarations {
+	for i := range d {
+		d[i].Package = slices.MultiTrimPrefix(d[i].Package, prefixes)
+	}
+
+	return d
+}
+
+func (d declarations) table(title string) table {
+	t := table{
+		Title: title,
+		Columns: []string{
+			"Package/file", "Package name", "Functions", "Exported functions", "Exported types",
+			"Avg function lines", "Max function lines",
+		},
+	}
+
+	for _, data := range d {
+		t.Rows = append(t.Rows, []string{
+			data.label(), data.PackageName, formatFloat(data.Functions), formatFloat(data.ExportedFunctions),
+			formatFloat(data.ExportedTypes), formatFloat(data.AvgFunctionLines), formatFloat(data.MaxFunctionLines),
+		})
+	}
+
+	return t
+}
+
+const declarationsChartID = "declarations"
+
+func declarationsChart(data declarations) (components.Charter, error) {
+	if len(data) > 30 {
+		data = data[:30]
+	}
+
+	js, err := data.table("Go declarations").js(declarationsChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// bar chart draws from bottom to top
+	reverted := append(declarations{}, data...)
+	slices.Revert(reverted)
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: declarationsChartID,
+			Width:   "100%",
+			Height:  fmt.Sprintf("%dpx", 200+20*len(reverted)),
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Function length",
+			Subtitle: "Go packages/files ordered by average function lines",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true}),
+		charts.WithLegendOpts(opts.Legend{Show: true}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Package",
+			Type: "category",
+			Show: true,
+			Data: slices.Map(reverted, declarationsData.label),
+			AxisLabel: &opts.AxisLabel{
+				Show:         true,
+				ShowMinLabel: true,
+				ShowMaxLabel: true,
+			},
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Show: true,
+			Name: "Lines",
+			Type: "value",
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	bar.AddSeries("Average", slices.Map(reverted, func(d declarationsData) opts.BarData {
+		return opts.BarData{Name: d.label(), Value: d.AvgFunctionLines}
+	}))
+	bar.AddSeries("Max", slices.Map(reverted, func(d declarationsData) opts.BarData {
+		return opts.BarData{Name: d.label(), Value: d.MaxFunctionLines}
+	}))
+	bar.AddJSFuncs(js)
+
+	return bar, nil
+}
@@ -0,0 +1,165 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
+)
+
+// hotspotData is file which is both big and frequently changed
+type hotspotData struct {
+	Alias    string           `json:"alias"`
+	Package  string           `json:"package"`
+	Name     string           `json:"name"`
+	Lines    float64          `json:"lines"`
+	Changes  float64          `json:"changes"`
+	Fixes    float64          `json:"fixes"`
+	Score    float64          `json:"score" gorm:"-"`
+	Priority project.Priority `json:"priority,omitempty" gorm:"-"`
+}
+
+func (d hotspotData) label() string {
+	return valueData{Alias: d.Alias, Package: d.Package, Name: d.Name, Priority: d.Priority}.label()
+}
+
+type hotspots []hotspotData
+
+// withScores sets hotspot score as product of line changes and lines normalized to 0..100 range
+func (h hotspots) withScores() hotspots {
+	maxChanges, maxLines := 0.0, 0.0
+	for _, d := range h {
+		maxChanges = math.Max(maxChanges, d.Changes)
+		maxLines = math.Max(maxLines, d.Lines)
+	}
+
+	if maxChanges == 0 || maxLines == 0 {
+		return h
+	}
+
+	for i, d := range h {
+		h[i].Score = math.Round(100 * d.Changes / maxChanges * d.Lines / maxLines)
+	}
+
+	return h
+}
+
+func (h hotspots) withPriorities(priorities map[string]project.Priority) hotspots {
+	for i := range h {
+		h[i].Priority = priorities[path.Join(h[i].Alias, h[i].Package)]
+	}
+
+	return h
+}
+
+func (h hotspots) withPackagesTrimmed(prefixes []string) hotspots {
+	for i := range h {
+		h[i].Package = slices.MultiTrimPrefix(h[i].Package, prefixes)
+	}
+
+	return h
+}
+
+func (h hotspots) maxFixes() (m float64) {
+	for _, d := range h {
+		m = math.Max(m, d.Fixes)
+	}
+
+	return m
+}
+
+// table returns hotspots ranked in the same order
+func (h hotspots) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"#", "File", "Score", "Line changes", "Lines", "Fix commits"},
+	}
+
+	for i, d := range h {
+		t.Rows = append(t.Rows, []string{
+			strconv.Itoa(i + 1), d.label(), formatFloat(d.Score), formatFloat(d.Changes), formatFloat(d.Lines), formatFloat(d.Fixes),
+		})
+	}
+
+	return t
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+const hotspotsChartID = "hotspots"
+
+func hotspotsChart(months int, sizeName string, data hotspots) (components.Charter, error) {
+	scatter := charts.NewScatter()
+	scatter.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: hotspotsChartID,
+			Width:   "100%",
+			Height:  "600px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Hotspots",
+			Subtitle: fmt.Sprintf("Big files with a lot of line changes in %d months. Colour is fix commits count", months),
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true, Formatter: "{b}<br/>changes, lines, fixes: {c}"}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Line changes",
+			Type: "value",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: sizeName,
+			Type: "value",
+		}),
+		charts.WithVisualMapOpts(opts.VisualMap{
+			Calculable: true,
+			Dimension:  "2",
+			Min:        0,
+			Max:        float32(data.maxFixes()),
+			InRange: &opts.VisualMapInRange{
+				Color: []string{"#a7d8de", "#eac736", "#d94e5d"},
+			},
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	scatter.AddSeries("Files", slices.Map(data, func(d hotspotData) opts.ScatterData {
+		return opts.ScatterData{
+			Name:       d.label(),
+			Value:      []float64{d.Changes, d.Lines, d.Fixes},
+			SymbolSize: 12,
+		}
+	}))
+
+	rows := data
+	if len(rows) > 20 {
+		rows = rows[:20]
+	}
+
+	js, err := rows.table("Top hotspots ordered by score").js(hotspotsChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	scatter.AddJSFuncs(js)
+
+	return scatter, nil
+}
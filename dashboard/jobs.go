@@ -0,0 +1,125 @@
+package dashboard
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+
+	"github.com/rusinikita/devex/datacollector"
+	database "github.com/rusinikita/devex/db"
+	"github.com/rusinikita/devex/project"
+)
+
+//go:embed jobs.gohtml
+var jobsPage string
+
+// running contains ids of projects with data collection in progress
+var running sync.Map
+
+type jobData struct {
+	project.DataFetchJob
+	Alias string
+}
+
+func jobsHandler(ctx *gin.Context) {
+	db := database.GetDB(ctx)
+
+	var projects []project.Project
+	if err := db.Find(&projects).Error; err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var jobs []jobData
+	err := db.Model(project.DataFetchJob{}).
+		Select("data_fetch_jobs.*", "alias").
+		Joins("join projects p on p.id = data_fetch_jobs.project").
+		Order("data_fetch_jobs.created_at desc").
+		Limit(50).
+		Scan(&jobs).
+		Error
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	data := struct {
+		Projects []project.Project
+		Jobs     []jobData
+		Running  bool
+	}{
+		Projects: projects,
+		Jobs:     jobs,
+	}
+
+	running.Range(func(_, _ any) bool {
+		data.Running = true
+		return false
+	})
+
+	ctx.Render(http.StatusOK, render.HTML{
+		Template: template.Must(template.New("jobs").Parse(jobsPage)),
+		Name:     "jobs",
+		Data:     data,
+	})
+}
+
+// startJobHandler runs project data update in background
+func startJobHandler(ctx *gin.Context) {
+	params := struct {
+		ProjectID project.ID `form:"project_id" binding:"required"`
+	}{}
+
+	if err := ctx.Bind(&params); err != nil {
+		return
+	}
+
+	db := database.GetDB(ctx)
+
+	p := project.Project{}
+	if err := db.Take(&p, params.ProjectID).Error; err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if _, loaded := running.LoadOrStore(p.ID, true); loaded {
+		ctx.Error(fmt.Errorf("%s data collection is already running", p.Alias)).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	go func() {
+		defer running.Delete(p.ID)
+
+		err := datacollector.Update(context.Background(), db, p)
+		if err != nil {
+			log.Println("collect error", p.Alias, err)
+		}
+	}()
+
+	ctx.Redirect(http.StatusSeeOther, "/jobs")
+}
+
+func jobHandler(ctx *gin.Context) {
+	params := struct {
+		ID project.ID `uri:"id" binding:"required"`
+	}{}
+
+	if err := ctx.BindUri(&params); err != nil {
+		return
+	}
+
+	job := project.DataFetchJob{}
+	if err := database.GetDB(ctx).Take(&job, params.ID).Error; err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,262 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/lucasb-eyer/go-colorful"
+
+	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
+)
+
+// knowledgeData is package/file authorship summary
+type knowledgeData struct {
+	Alias   string  `json:"alias"`
+	Package string  `json:"package"`
+	Name    string  `json:"name,omitempty"`
+	Changes float64 `json:"changes"`
+	Authors int     `json:"authors"`
+	// TruckFactor is minimal authors count with more than half of line changes
+	TruckFactor     int     `json:"truck_factor"`
+	MainAuthor      string  `json:"main_author"`
+	MainAuthorShare float64 `json:"main_author_share"`
+	// Orphaned is share of line changes made by authors without commits in last months
+	Orphaned float64          `json:"orphaned"`
+	Priority project.Priority `json:"priority,omitempty"`
+}
+
+func (d knowledgeData) label() string {
+	return valueData{Alias: d.Alias, Package: d.Package, Name: d.Name, Priority: d.Priority}.label()
+}
+
+// orphanedChanges is line changes count of not active authors, it is knowledge loss rank
+func (d knowledgeData) orphanedChanges() float64 {
+	return math.Round(d.Orphaned * d.Changes)
+}
+
+type knowledges []knowledgeData
+
+// knowledge calculates authorship metrics from authors line changes per package/file.
+// Authors are active if they are in active set of the project.
+func knowledge(contributions values, active values) (result knowledges) {
+	activeAuthors := slices.ToSet(slices.Map(active, func(d valueData) string {
+		return d.Alias + "/" + d.Author
+	}))
+
+	byKey := map[string]values{}
+	for _, c := range contributions {
+		key := path.Join(c.Alias, c.Package, c.Name)
+		byKey[key] = append(byKey[key], c)
+	}
+
+	for _, authors := range byKey {
+		sort.Slice(authors, func(i, j int) bool {
+			return authors[i].Value > authors[j].Value
+		})
+
+		total := slices.Fold(authors, func(a valueData, sum float64) float64 {
+			return sum + a.Value
+		})
+		if total == 0 {
+			continue
+		}
+
+		d := knowledgeData{
+			Alias:           authors[0].Alias,
+			Package:         authors[0].Package,
+			Name:            authors[0].Name,
+			Changes:         total,
+			Authors:         len(authors),
+			MainAuthor:      authors[0].Author,
+			MainAuthorShare: math.Round(100*authors[0].Value/total) / 100,
+		}
+
+		covered, orphaned := 0.0, 0.0
+		for _, a := range authors {
+			if covered <= total/2 {
+				covered += a.Value
+				d.TruckFactor++
+			}
+
+			if !activeAuthors[a.Alias+"/"+a.Author] {
+				orphaned += a.Value
+			}
+		}
+
+		d.Orphaned = math.Round(100*orphaned/total) / 100
+
+		result = append(result, d)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].orphanedChanges() != result[j].orphanedChanges() {
+			return result[i].orphanedChanges() > result[j].orphanedChanges()
+		}
+
+		if result[i].TruckFactor != result[j].TruckFactor {
+			return result[i].TruckFactor < result[j].TruckFactor
+		}
+
+		return result[i].Changes > result[j].Changes
+	})
+
+	return result
+}
+
+func (k knowledges) withPriorities(priorities map[string]project.Priority) knowledges {
+	for i := range k {
+		k[i].Priority = priorities[path.Join(k[i].Alias, k[i].Package)]
+	}
+
+	return k
+}
+
+func (k knowledges) withPackagesTrimmed(prefixes []string) knowledges {
+	for i := range k {
+		k[i].Package = slices.MultiTrimPrefix(k[i].Package, prefixes)
+	}
+
+	return k
+}
+
+func (k knowledges) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"Package/file", "Truck factor", "Authors", "Main author", "Main author share", "Orphaned share"},
+	}
+
+	for _, d := range k {
+		t.Rows = append(t.Rows, []string{
+			d.label(), fmt.Sprint(d.TruckFactor), fmt.Sprint(d.Authors), d.MainAuthor, formatFloat(d.MainAuthorShare), formatFloat(d.Orphaned),
+		})
+	}
+
+	return t
+}
+
+const (
+	riskColor = "#d94e5d"
+	warnColor = "#eac736"
+	safeColor = "#50a3ba"
+)
+
+func truckFactorColor(truckFactor int) string {
+	switch truckFactor {
+	case 1:
+		return riskColor
+	case 2:
+		return warnColor
+	default:
+		return safeColor
+	}
+}
+
+// riskShareColor blends safe and risk colors by risk share, e.g. orphaned share
+func riskShareColor(share float64) string {
+	safe, _ := colorful.Hex(safeColor)
+	risk, _ := colorful.Hex(riskColor)
+
+	return safe.BlendLab(risk, share).Clamped().Hex()
+}
+
+const (
+	treemapColorTruckFactor = "truck_factor"
+	treemapColorOrphaned    = "orphaned"
+)
+
+// withKnowledgeColors sets packages colors by knowledge metric
+func (v values) withKnowledgeColors(mode string, packages knowledges) values {
+	colors := map[string]string{}
+	for _, d := range packages {
+		switch mode {
+		case treemapColorTruckFactor:
+			colors[path.Join(d.Alias, d.Package)] = truckFactorColor(d.TruckFactor)
+		case treemapColorOrphaned:
+			colors[path.Join(d.Alias, d.Package)] = riskShareColor(d.Orphaned)
+		}
+	}
+
+	for i := range v {
+		v[i].Color = colors[path.Join(v[i].Alias, v[i].Package)]
+	}
+
+	return v
+}
+
+const knowledgeChartID = "knowledge"
+
+func knowledgeChart(months int, data knowledges) (components.Charter, error) {
+	if len(data) > 30 {
+		data = data[:30]
+	}
+
+	js, err := data.table("Authorship").js(knowledgeChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	reverted := append(knowledges{}, data...)
+	slices.Revert(reverted)
+
+	barData := slices.Map(reverted, func(d knowledgeData) opts.BarData {
+		return opts.BarData{
+			Name:      d.label(),
+			Value:     d.orphanedChanges(),
+			ItemStyle: &opts.ItemStyle{Color: truckFactorColor(d.TruckFactor)},
+		}
+	})
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: knowledgeChartID,
+			Width:   "100%",
+			Height:  fmt.Sprintf("%dpx", 200+20*len(barData)),
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title: "Knowledge loss risk",
+			Subtitle: fmt.Sprintf("Line changes by authors without commits in %d months. "+
+				"Red - truck factor 1, yellow - truck factor 2", months),
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Package",
+			Type: "category",
+			Show: true,
+			Data: slices.Map(reverted, knowledgeData.label),
+			AxisLabel: &opts.AxisLabel{
+				Show:         true,
+				ShowMinLabel: true,
+				ShowMaxLabel: true,
+			},
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Show: true,
+			Name: "Orphaned lines",
+			Type: "value",
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	bar.AddSeries("", barData)
+	bar.AddJSFuncs(js)
+
+	return bar, nil
+}
@@ -0,0 +1,155 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/rusinikita/devex/slices"
+)
+
+// languageData is project files and lines count of language
+type languageData struct {
+	Alias    string  `json:"alias"`
+	Language string  `json:"language"`
+	Files    float64 `json:"files"`
+	Lines    float64 `json:"lines"`
+	// Share is part of project lines
+	Share float64 `json:"share" gorm:"-"`
+}
+
+const unknownLanguage = "unknown"
+
+func (d languageData) label() string {
+	if d.Language == "" {
+		return unknownLanguage
+	}
+
+	return d.Language
+}
+
+type languages []languageData
+
+func (l languages) withShares() languages {
+	totals := map[string]float64{}
+	for _, d := range l {
+		totals[d.Alias] += d.Lines
+	}
+
+	for i, d := range l {
+		if totals[d.Alias] > 0 {
+			l[i].Share = math.Round(100*d.Lines/totals[d.Alias]) / 100
+		}
+	}
+
+	return l
+}
+
+func (l languages) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"Project", "Language", "Files", "Lines", "Share"},
+	}
+
+	for _, d := range l {
+		t.Rows = append(t.Rows, []string{
+			d.Alias, d.label(), formatFloat(d.Files), formatFloat(d.Lines), formatFloat(d.Share),
+		})
+	}
+
+	return t
+}
+
+const languagesChartID = "languages"
+
+// languagesChart shows projects lines stacked by language, languages are ordered by total lines
+func languagesChart(data languages) (components.Charter, error) {
+	js, err := data.table("Languages").js(languagesChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := slices.Distinct(slices.Map(data, func(d languageData) string { return d.Alias }))
+	sort.Strings(aliases)
+
+	aliasIndex := map[string]int{}
+	for i, alias := range aliases {
+		aliasIndex[alias] = i
+	}
+
+	languageLines := map[string]float64{}
+	for _, d := range data {
+		languageLines[d.label()] += d.Lines
+	}
+
+	names := slices.Distinct(slices.Map(data, languageData.label))
+	sort.Slice(names, func(i, j int) bool {
+		if languageLines[names[i]] != languageLines[names[j]] {
+			return languageLines[names[i]] > languageLines[names[j]]
+		}
+
+		return names[i] < names[j]
+	})
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: languagesChartID,
+			Width:   "100%",
+			Height:  fmt.Sprintf("%dpx", 200+40*len(aliases)),
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Languages",
+			Subtitle: "Code lines per detected file language",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true}),
+		charts.WithLegendOpts(opts.Legend{Show: true, Top: "bottom", Type: "scroll"}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Project",
+			Type: "category",
+			Show: true,
+			Data: aliases,
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Show: true,
+			Name: "Lines",
+			Type: "value",
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+			Bottom:       "60",
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	for _, name := range names {
+		barData := make([]opts.BarData, len(aliases))
+		for i, alias := range aliases {
+			barData[i] = opts.BarData{Name: alias}
+		}
+
+		for _, d := range data {
+			if d.label() == name {
+				barData[aliasIndex[d.Alias]].Value = d.Lines
+			}
+		}
+
+		bar.AddSeries(name, barData, charts.WithBarChartOpts(opts.BarChart{Stack: "lines"}))
+	}
+
+	bar.AddJSFuncs(js)
+
+	return bar, nil
+}
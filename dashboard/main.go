@@ -5,6 +5,7 @@ import (
 	_ "embed"
 	"fmt"
 	"html/template"
+	"io"
 	"net/http"
 	"strings"
 
@@ -13,7 +14,9 @@ import (
 	"github.com/go-echarts/go-echarts/v2/templates"
 	"gorm.io/gorm"
 
+	"github.com/rusinikita/devex/datacollector"
 	database "github.com/rusinikita/devex/db"
+	"github.com/rusinikita/devex/filter"
 	"github.com/rusinikita/devex/project"
 	"github.com/rusinikita/devex/slices"
 )
@@ -25,152 +28,631 @@ type Params struct {
 	ProjectIDs      []project.ID `form:"project_ids"`
 	PerFiles        bool         `form:"per_files"`
 	PerFilesImports bool         `form:"per_files_imports"`
+	PerTeams        bool         `form:"per_teams"`
 	PackageFilter   string       `form:"package_filter"`
 	NameFilter      string       `form:"name_filter"`
+	PriorityFilter  string       `form:"priority_filter"`
+	LanguageFilter  string       `form:"language_filter"`
 	TrimPackage     string       `form:"trim_package"`
 	CommitFilters   string       `form:"commit_filters"`
 	FileFilters     string       `form:"file_filters"`
+	RevisionFrom    project.ID   `form:"revision_from"`
+	RevisionTo      project.ID   `form:"revision_to"`
+	HotspotMonths   int          `form:"hotspot_months"`
+	KnowledgeMonths int          `form:"knowledge_months"`
+	TreemapColor    string       `form:"treemap_color"`
+	SizeBy          string       `form:"size_by"`
 }
 
-func (p Params) sqlFilter() (sql string) {
-	if p.PackageFilter != "" {
-		sql += "and " + slices.SQLFilter("package", p.PackageFilter)
+// filesFilter returns files condition built from package, name, priority and language filters.
+// Files table must be aliased as 'f', because joined tables like revisions have project column too.
+func (p Params) filesFilter() (filter.SQL, error) {
+	packageFilter, err := parseFilter("package_filter", p.PackageFilter, "f.package")
+	if err != nil {
+		return filter.SQL{}, err
+	}
+
+	nameFilter, err := parseFilter("name_filter", p.NameFilter, "f.name")
+	if err != nil {
+		return filter.SQL{}, err
+	}
+
+	priorityFilter, err := parseFilter("priority_filter", p.PriorityFilter, "priority")
+	if err != nil {
+		return filter.SQL{}, err
+	}
+
+	if priorityFilter.Query != "" {
+		priorityFilter.Query = "f.project || '/' || f.package in (select project || '/' || path from packages where " +
+			priorityFilter.Query + ")"
+	}
+
+	languageFilter, err := parseFilter("language_filter", p.LanguageFilter, "language")
+	if err != nil {
+		return filter.SQL{}, err
+	}
+
+	// projects table has language column too, so files language is checked in subquery
+	if languageFilter.Query != "" {
+		languageFilter.Query = "f.project || '/' || f.package || '/' || f.name in " +
+			"(select project || '/' || package || '/' || name from files where " + languageFilter.Query + ")"
+	}
+
+	return packageFilter.And(nameFilter).And(priorityFilter).And(languageFilter), nil
+}
+
+func (p Params) commitsFilter() (filter.SQL, error) {
+	return parseFilter("commit_filters", p.CommitFilters, "c.message")
+}
+
+// fixesFilter returns fix commits condition, commit filters are used if set.
+// Filters without values like ";" are empty conditions, so default filter is used for them too.
+func (p Params) fixesFilter() (filter.SQL, error) {
+	commitsFilter, err := p.commitsFilter()
+	if err != nil || commitsFilter.Query != "" {
+		return commitsFilter, err
+	}
+
+	return parseFilter("commit_filters", defaultFixesFilter, "c.message")
+}
+
+const defaultFixesFilter = "fix,bug"
+
+// hotspotMonths returns hotspots churn period, it is 12 months by default
+func (p Params) hotspotMonths() int {
+	if p.HotspotMonths <= 0 {
+		return 12
+	}
+
+	return p.HotspotMonths
+}
+
+// knowledgeMonths returns period authors are active in, it is 6 months by default
+func (p Params) knowledgeMonths() int {
+	if p.KnowledgeMonths <= 0 {
+		return 6
+	}
+
+	return p.KnowledgeMonths
+}
+
+const sizeByCodeLines = "code_lines"
+
+// sizeColumn returns files size column for treemap and hotspots, it is all lines by default
+func (p Params) sizeColumn() string {
+	if p.SizeBy == sizeByCodeLines {
+		return "code_lines"
 	}
 
-	if p.NameFilter != "" {
-		sql += " and " + slices.SQLFilter("name", p.NameFilter)
+	return "lines"
+}
+
+// sizeName returns files size description, code lines are lines without comments and blank lines
+func (p Params) sizeName() string {
+	if p.SizeBy == sizeByCodeLines {
+		return "Code lines"
+	}
+
+	return "Lines"
+}
+
+func (p Params) tagsFilter() (filter.SQL, error) {
+	return parseFilter("file_filters", p.FileFilters, "tags")
+}
+
+// filterErrors returns filter syntax errors by form field name
+func (p Params) filterErrors() map[string]string {
+	fields := map[string]string{
+		"package_filter":  p.PackageFilter,
+		"name_filter":     p.NameFilter,
+		"priority_filter": p.PriorityFilter,
+		"language_filter": p.LanguageFilter,
+		"commit_filters":  p.CommitFilters,
+		"file_filters":    p.FileFilters,
+	}
+
+	errs := map[string]string{}
+
+	for field, value := range fields {
+		if _, err := filter.Parse(value); err != nil {
+			errs[field] = err.Error()
+		}
+	}
+
+	return errs
+}
+
+// filterError is form field filter syntax error, it is bad params error for API
+type filterError struct {
+	Field string
+	Err   error
+}
+
+func (e filterError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+func (e filterError) Unwrap() error {
+	return e.Err
+}
+
+func (e filterError) Is(target error) bool {
+	return target == errBadParams
+}
+
+func parseFilter(field, value, column string) (filter.SQL, error) {
+	expr, err := filter.Parse(value)
+	if err != nil {
+		return filter.SQL{}, filterError{Field: field, Err: err}
 	}
 
-	return sql
+	return expr.SQL(column), nil
 }
 
-func renderPage(db *gorm.DB, params Params, w http.ResponseWriter) error {
+// dataProjects returns selected projects ids, all projects are used if nothing selected
+func (p Params) dataProjects(projects []project.Project) []project.ID {
+	if len(p.ProjectIDs) > 0 {
+		return p.ProjectIDs
+	}
+
+	return slices.Map(projects, func(p project.Project) project.ID {
+		return p.ID
+	})
+}
+
+// renderPage renders dashboard, report page has read only form
+func renderPage(db *gorm.DB, params Params, report bool, w io.Writer) error {
 	// REQUEST
 	var projects []project.Project
 	if err := db.Find(&projects).Error; err != nil {
 		return err
 	}
 
-	dataProjects := params.ProjectIDs
-	if len(params.ProjectIDs) == 0 {
-		for _, p := range projects {
-			dataProjects = append(dataProjects, p.ID)
+	dataProjects := params.dataProjects(projects)
+
+	// RENDER
+	page := components.NewPage()
+
+	// charts are not rendered until filters are fixed
+	filterErrors := params.filterErrors()
+	if len(filterErrors) == 0 {
+		charts, err := pageCharts(db, params, dataProjects)
+		if err != nil {
+			return err
 		}
+
+		page.AddCharts(charts...)
 	}
 
-	sqlFilter := params.sqlFilter()
-	packagePrefs := strings.Split(params.TrimPackage, ",")
+	page.SetLayout(components.PageNoneLayout)
+	page.AddCustomizedCSSAssets("https://cdn.jsdelivr.net/npm/@picocss/pico@1/css/pico.min.css")
+
+	projectRevisions, err := revisions(db, dataProjects)
+	if err != nil {
+		return err
+	}
+
+	// template hack
+	originTpl := templates.PageTpl
+	defer func() { templates.PageTpl = originTpl }()
+
+	var tpl bytes.Buffer
+	formData := struct {
+		Projects           []project.Project
+		SelectedProjects   slices.Set[project.ID]
+		PerFiles           bool
+		PerFilesImports    bool
+		PerTeams           bool
+		PackageFilter      string
+		NameFilter         string
+		PriorityFilter     string
+		LanguageFilter     string
+		Priorities         []project.Priority
+		TrimPackage        string
+		CommitFilters      string
+		FileFilters        string
+		Revisions          []revisionData
+		RevisionFrom       project.ID
+		RevisionTo         project.ID
+		HotspotMonths      int
+		KnowledgeMonths    int
+		TreemapColor       string
+		SizeBy             string
+		DefaultFixesFilter string
+		Errors             map[string]string
+		Report             bool
+	}{
+		Projects:           projects,
+		SelectedProjects:   slices.ToSet(params.ProjectIDs),
+		PerFiles:           params.PerFiles,
+		PerFilesImports:    params.PerFilesImports,
+		PerTeams:           params.PerTeams,
+		PackageFilter:      params.PackageFilter,
+		NameFilter:         params.NameFilter,
+		PriorityFilter:     params.PriorityFilter,
+		LanguageFilter:     params.LanguageFilter,
+		Priorities:         project.Priorities,
+		TrimPackage:        params.TrimPackage,
+		CommitFilters:      params.CommitFilters,
+		FileFilters:        params.FileFilters,
+		Revisions:          projectRevisions,
+		RevisionFrom:       params.RevisionFrom,
+		RevisionTo:         params.RevisionTo,
+		HotspotMonths:      params.hotspotMonths(),
+		KnowledgeMonths:    params.knowledgeMonths(),
+		TreemapColor:       params.TreemapColor,
+		SizeBy:             params.SizeBy,
+		DefaultFixesFilter: defaultFixesFilter,
+		Errors:             filterErrors,
+		Report:             report,
+	}
 
-	filesTop, err := gitChangesTop(db, params.PerFiles, dataProjects, sqlFilter)
+	err = template.Must(template.New("new").Parse(form)).Execute(&tpl, formData)
 	if err != nil {
 		return err
 	}
 
+	templates.PageTpl = strings.ReplaceAll(templates.PageTpl, "<body>", "<body class=\"container\">\n"+tpl.String())
+	templates.PageTpl = strings.ReplaceAll(templates.PageTpl, "<html>", "<html data-theme=\"light\">")
+
+	return page.Render(w)
+}
+
+func pageCharts(db *gorm.DB, params Params, dataProjects []project.ID) (charts []components.Charter, err error) {
+	filesFilter, err := params.filesFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	commitsFilter, err := params.commitsFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	tagsFilter, err := params.tagsFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	packagePrefs := strings.Split(params.TrimPackage, ",")
+
+	priorities, err := packagePriorities(db, dataProjects)
+	if err != nil {
+		return nil, err
+	}
+
+	filesTop, err := gitChangesTop(db, params.PerFiles, params.PerTeams, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	filesTop = filesTop.withPriorities(priorities)
+
 	heatmapBars := filesTop
 	if len(filesTop) > 20 {
 		heatmapBars = filesTop[:20]
 	}
 
-	data, err := gitChangesData(db, params.PerFiles, dataProjects, heatmapBars)
+	data, err := gitChangesData(db, params.PerFiles, params.PerTeams, dataProjects, heatmapBars)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// RENDER
-	page := components.NewPage()
+	data = data.withPriorities(priorities)
 
-	page.AddCharts(heatmap(heatmapBars.withPackagesTrimmed(packagePrefs).barNames(), data.withPackagesTrimmed(packagePrefs)))
+	charts = append(charts, heatmap(heatmapBars.withPackagesTrimmed(packagePrefs).labels(), data.withPackagesTrimmed(packagePrefs)))
 
-	page.AddCharts(bar("Top changes speed", "List of packages/files ordered by average change lines per month speed", filesTop))
+	charts = append(charts, bar("Top changes speed", "List of packages/files ordered by average change lines per month speed", filesTop))
 
-	sizes, err := fileSizes(db, dataProjects, sqlFilter)
+	sizes, err := fileSizes(db, dataProjects, params.sizeColumn(), filesFilter)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	filesCoverage, err := fileCoverage(db, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes = sizes.withPriorities(priorities).withCoverage(filesCoverage)
+
+	switch {
+	case params.TreemapColor == treemapColorCoverage:
+		sizes = sizes.withCoverageColors()
+	case params.TreemapColor == treemapColorComplexity:
+		complexities, err := fileSizes(db, dataProjects, "cognitive_complexity", filesFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		sizes = sizes.withComplexityColors(complexities)
+	case params.TreemapColor != "":
+		packagesKnowledge, err := authorsKnowledge(db, false, dataProjects, filesFilter, params.knowledgeMonths())
+		if err != nil {
+			return nil, err
+		}
+
+		sizes = sizes.withKnowledgeColors(params.TreemapColor, packagesKnowledge)
 	}
 
-	page.AddCharts(treeMap(sizes.withPackagesTrimmed(packagePrefs)))
+	charts = append(charts, treeMap("Project "+strings.ToLower(params.sizeName())+" count"+treemapSubtitles[params.TreemapColor], params.sizeName(), sizes.withPackagesTrimmed(packagePrefs)))
 
-	fileCommits, err := commitMessages(db, params.PerFiles, dataProjects, sqlFilter, " and "+slices.SQLFilter("c.message", params.CommitFilters))
+	languagesData, err := fileLanguages(db, dataProjects, filesFilter)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fileCommits = fileCommits.withPackagesTrimmed(packagePrefs)
+	languagesChart, err := languagesChart(languagesData.withShares())
+	if err != nil {
+		return nil, err
+	}
 
-	page.AddCharts(bar("Commits", fmt.Sprintf("Changes with '%s' filter applied to file", params.CommitFilters), fileCommits))
+	charts = append(charts, languagesChart)
 
-	fileTagsData, err := fileTags(db, dataProjects, sqlFilter, " and "+slices.SQLFilter("tags", params.FileFilters))
+	goDeclarationsData, err := goDeclarations(db, params.PerFiles, dataProjects, filesFilter)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if len(goDeclarationsData) > 0 {
+		declarationsChart, err := declarationsChart(goDeclarationsData.withPriorities(priorities).withPackagesTrimmed(packagePrefs))
+		if err != nil {
+			return nil, err
+		}
+
+		charts = append(charts, declarationsChart)
 	}
 
-	fileTagsData = fileTagsData.tagsToValue(params.FileFilters).withPackagesTrimmed(packagePrefs)
+	functionsData, err := complexFunctions(db, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
 
-	page.AddCharts(bar("File tags", fmt.Sprintf("Files with tags from '%s' filter in file content", params.FileFilters), fileTagsData))
+	if len(functionsData) > 0 {
+		complexityChart, err := complexityChart(functionsData.withPriorities(priorities).withPackagesTrimmed(packagePrefs))
+		if err != nil {
+			return nil, err
+		}
 
-	// fileContents, err := commitMessages(db, params.PerFiles, dataProjects, sqlFilter, " and "+SQLFilter("c.message", params.CommitFilters))
-	// if err != nil {
-	// 	return err
-	// }
-	//
-	// page.AddCharts(bar("Contents", "Files with keywords in content",fileContents))
+		charts = append(charts, complexityChart)
+	}
 
-	contibs, err := contribution(db, params.PerFiles, dataProjects, sqlFilter)
+	fixesFilter, err := params.fixesFilter()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	page.AddCharts(sandkey(contibs.withPackagesTrimmed(packagePrefs)))
+	fileHotspotsData, err := fileHotspots(db, dataProjects, params.sizeColumn(), params.hotspotMonths(), filesFilter, fixesFilter)
+	if err != nil {
+		return nil, err
+	}
 
-	fileImports, err := imports(db, params.PerFilesImports, dataProjects, sqlFilter)
+	hotspotsChart, err := hotspotsChart(params.hotspotMonths(), params.sizeName(), fileHotspotsData.withPriorities(priorities).withPackagesTrimmed(packagePrefs))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	page.AddCharts(circularGraph(fileImports.withPackagesTrimmed(packagePrefs)))
+	charts = append(charts, hotspotsChart)
 
-	page.SetLayout(components.PageNoneLayout)
-	page.AddCustomizedCSSAssets("https://cdn.jsdelivr.net/npm/@picocss/pico@1/css/pico.min.css")
+	riskyFilesData, err := riskyFiles(db, dataProjects, params.hotspotMonths(), filesFilter)
+	if err != nil {
+		return nil, err
+	}
 
-	// template hack
-	originTpl := templates.PageTpl
-	defer func() { templates.PageTpl = originTpl }()
+	if len(riskyFilesData) > 0 {
+		riskyChart, err := riskyFilesChart(params.hotspotMonths(), riskyFilesData.withPriorities(priorities).withPackagesTrimmed(packagePrefs))
+		if err != nil {
+			return nil, err
+		}
 
-	var tpl bytes.Buffer
-	formData := struct {
-		Projects         []project.Project
-		SelectedProjects slices.Set[project.ID]
-		PerFiles         bool
-		PerFilesImports  bool
-		PackageFilter    string
-		NameFilter       string
-		TrimPackage      string
-		CommitFilters    string
-		FileFilters      string
-	}{
-		Projects:         projects,
-		SelectedProjects: slices.ToSet(params.ProjectIDs),
-		PerFiles:         params.PerFiles,
-		PerFilesImports:  params.PerFilesImports,
-		PackageFilter:    params.PackageFilter,
-		NameFilter:       params.NameFilter,
-		TrimPackage:      params.TrimPackage,
-		CommitFilters:    params.CommitFilters,
-		FileFilters:      params.FileFilters,
+		charts = append(charts, riskyChart)
 	}
 
-	err = template.Must(template.New("new").Parse(form)).Execute(&tpl, formData)
+	projectsCoverage, err := coverageHistory(db, false, dataProjects, filesFilter)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	templates.PageTpl = strings.ReplaceAll(templates.PageTpl, "<body>", "<body class=\"container\">\n"+tpl.String())
-	templates.PageTpl = strings.ReplaceAll(templates.PageTpl, "<html>", "<html data-theme=\"light\">")
+	if len(projectsCoverage) > 0 {
+		packagesCoverage, err := coverageHistory(db, true, dataProjects, filesFilter)
+		if err != nil {
+			return nil, err
+		}
 
-	return page.Render(w)
+		historyChart, err := coverageHistoryChart(projectsCoverage, packagesCoverage.withPriorities(priorities).withPackagesTrimmed(packagePrefs))
+		if err != nil {
+			return nil, err
+		}
+
+		charts = append(charts, historyChart)
+	}
+
+	fileCommits, err := commitMessages(db, params.PerFiles, params.PerTeams, dataProjects, filesFilter, commitsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	fileCommits = fileCommits.withPriorities(priorities).withPackagesTrimmed(packagePrefs)
+
+	charts = append(charts, bar("Commits", fmt.Sprintf("Changes with '%s' filter applied to file", params.CommitFilters), fileCommits))
+
+	fileTagsData, err := fileTags(db, dataProjects, filesFilter, tagsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	fileTagsData = fileTagsData.tagsToValue(params.FileFilters).withPriorities(priorities).withPackagesTrimmed(packagePrefs)
+
+	charts = append(charts, bar("File tags", fmt.Sprintf("Files with tags from '%s' filter in file content", params.FileFilters), fileTagsData))
+
+	if params.RevisionFrom > 0 && params.RevisionTo > 0 {
+		revisionCharts, err := revisionsCharts(db, params, filesFilter, packagePrefs)
+		if err != nil {
+			return nil, err
+		}
+
+		charts = append(charts, revisionCharts...)
+	}
+
+	couplingData, err := changeCoupling(db, params.PerFiles, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	couplingImports, err := imports(db, params.PerFiles, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	couplingData = couplingData.withImports(couplingImports).withPriorities(priorities).withPackagesTrimmed(packagePrefs)
+
+	couplingChart, err := couplingChart(couplingData)
+	if err != nil {
+		return nil, err
+	}
+
+	charts = append(charts, couplingChart)
+
+	knowledgeData, err := authorsKnowledge(db, params.PerFiles, dataProjects, filesFilter, params.knowledgeMonths())
+	if err != nil {
+		return nil, err
+	}
+
+	knowledgeChart, err := knowledgeChart(params.knowledgeMonths(), knowledgeData.withPriorities(priorities).withPackagesTrimmed(packagePrefs))
+	if err != nil {
+		return nil, err
+	}
+
+	charts = append(charts, knowledgeChart)
+
+	contibs, err := contribution(db, params.PerFiles, params.PerTeams, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	charts = append(charts, sandkey(contibs.withPriorities(priorities).withPackagesTrimmed(packagePrefs)))
+
+	fileImports, err := imports(db, params.PerFilesImports, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	charts = append(charts, circularGraph(fileImports.withPackagesTrimmed(packagePrefs)))
+
+	packagesStability, err := packageStability(db, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	stabilityChart, err := stabilityChart(packagesStability.withPriorities(priorities).withPackagesTrimmed(packagePrefs))
+	if err != nil {
+		return nil, err
+	}
+
+	charts = append(charts, stabilityChart)
+
+	violationsData, err := architectureViolations(db, dataProjects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	architectureChart, err := architectureChart(violationsData.withPackagesTrimmed(packagePrefs))
+	if err != nil {
+		return nil, err
+	}
+
+	charts = append(charts, architectureChart)
+
+	return charts, nil
+}
+
+// packageStability returns packages coupling and Go packages abstractness metrics
+func packageStability(db *gorm.DB, projects []project.ID, filesFilter filter.SQL) (stabilities, error) {
+	fileImports, err := imports(db, true, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	decls, err := goDeclarations(db, false, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return stability(fileImports, decls), nil
+}
+
+// authorsKnowledge returns authorship metrics of packages/files
+func authorsKnowledge(db *gorm.DB, filesMode bool, projects []project.ID, filesFilter filter.SQL, months int) (knowledges, error) {
+	contributions, err := authorChanges(db, filesMode, projects, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := activeAuthors(db, projects, months)
+	if err != nil {
+		return nil, err
+	}
+
+	return knowledge(contributions, active), nil
+}
+
+// revisionsCharts compares selected revisions data, coverage revisions have coverage changes only
+func revisionsCharts(db *gorm.DB, params Params, filesFilter filter.SQL, packagePrefs []string) (charts []components.Charter, err error) {
+	from, to := params.RevisionFrom, params.RevisionTo
+
+	coverageOnly, err := checkRevisions(db, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage, err := revisionCoverage(db, params.PerFiles, from, to, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	coverageChart := bar("Coverage changes", "Coverage percent difference between selected revisions", coverage.withPackagesTrimmed(packagePrefs))
+
+	if coverageOnly {
+		return []components.Charter{coverageChart}, nil
+	}
+
+	sizes, err := revisionSizes(db, params.PerFiles, params.sizeColumn(), from, to, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	charts = append(charts, bar("Size changes", fmt.Sprintf("%s difference between selected revisions", params.sizeName()), sizes.withPackagesTrimmed(packagePrefs)))
+
+	tags, err := revisionTags(db, params.PerFiles, from, to, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	tags = tags.tagsDiff(params.FileFilters).withPackagesTrimmed(packagePrefs)
+
+	charts = append(charts, bar("Tags changes", fmt.Sprintf("Tags from '%s' filter difference between selected revisions", params.FileFilters), tags))
+	charts = append(charts, coverageChart)
+
+	lintErrors, err := revisionLintErrors(db, params.PerFiles, from, to, filesFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	charts = append(charts, bar("Lint errors changes", "Lint errors count difference between selected revisions", lintErrors.withPackagesTrimmed(packagePrefs)))
+
+	return charts, nil
 }
 
 func RunServer(db *gorm.DB) error {
+	// jobs are run in server process, so unfinished jobs of previous run will never finish
+	if err := datacollector.FailInterruptedJobs(db); err != nil {
+		return err
+	}
+
+	return newEngine(db).Run(":1080")
+}
+
+func newEngine(db *gorm.DB) *gin.Engine {
 	engine := gin.New()
 
 	engine.Use(func(ctx *gin.Context) {
@@ -200,11 +682,17 @@ func RunServer(db *gorm.DB) error {
 			return
 		}
 
-		err := renderPage(database.GetDB(ctx), params, ctx.Writer)
+		err := renderPage(database.GetDB(ctx), params, false, ctx.Writer)
 		if err != nil {
 			ctx.Error(err)
 		}
 	})
 
-	return engine.Run(":1080")
+	engine.GET("/jobs", jobsHandler)
+	engine.POST("/jobs", startJobHandler)
+	engine.GET("/jobs/:id", jobHandler)
+
+	registerAPI(engine.Group("/api/v1"))
+
+	return engine
 }
@@ -0,0 +1,133 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
+
+	"github.com/rusinikita/devex/project"
+)
+
+// ReportParams returns dashboard params for projects with aliases.
+// Other params are parsed from dashboard URL query, e.g. "per_files=true&name_filter=*.go".
+func ReportParams(db *gorm.DB, aliases []string, query string) (params Params, err error) {
+	form, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return params, fmt.Errorf("params query: %w", err)
+	}
+
+	if err = binding.MapFormWithTag(&params, form, "form"); err != nil {
+		return params, fmt.Errorf("params query: %w", err)
+	}
+
+	if len(aliases) == 0 {
+		return params, nil
+	}
+
+	var projects []project.Project
+	if err = db.Find(&projects, "alias in ?", aliases).Error; err != nil {
+		return params, err
+	}
+
+	if len(projects) != len(aliases) {
+		return params, fmt.Errorf("projects %v not found, got %d of them", aliases, len(projects))
+	}
+
+	params.ProjectIDs = nil
+	for _, p := range projects {
+		params.ProjectIDs = append(params.ProjectIDs, p.ID)
+	}
+
+	return params, nil
+}
+
+// RenderReport renders dashboard page as self-contained HTML file.
+// Scripts and styles are downloaded and inlined, so report works without devex server.
+func RenderReport(ctx context.Context, db *gorm.DB, params Params, w io.Writer) error {
+	if errs := params.filterErrors(); len(errs) > 0 {
+		return fmt.Errorf("%w: filters %v", errBadParams, errs)
+	}
+
+	page := bytes.Buffer{}
+
+	if err := renderPage(db, params, true, &page); err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: time.Minute}
+
+	html, err := inlineAssets(ctx, client, page.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(html)
+
+	return err
+}
+
+var (
+	scriptAsset = regexp.MustCompile(`<script src="(https?://[^"]+)"></script>`)
+	styleAsset  = regexp.MustCompile(`<link href="(https?://[^"]+)" rel="stylesheet">`)
+)
+
+// inlineAssets replaces page remote scripts and styles with their content
+func inlineAssets(ctx context.Context, client *http.Client, page []byte) (_ []byte, err error) {
+	assets := map[string][]byte{}
+
+	for _, asset := range append(scriptAsset.FindAllSubmatch(page, -1), styleAsset.FindAllSubmatch(page, -1)...) {
+		assetURL := string(asset[1])
+		if _, ok := assets[assetURL]; ok {
+			continue
+		}
+
+		assets[assetURL], err = downloadAsset(ctx, client, assetURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	page = scriptAsset.ReplaceAllFunc(page, func(tag []byte) []byte {
+		content := assets[string(scriptAsset.FindSubmatch(tag)[1])]
+		content = bytes.ReplaceAll(content, []byte("</script"), []byte(`<\/script`))
+
+		return append(append([]byte("<script>\n"), content...), "\n</script>"...)
+	})
+
+	page = styleAsset.ReplaceAllFunc(page, func(tag []byte) []byte {
+		content := assets[string(styleAsset.FindSubmatch(tag)[1])]
+
+		return append(append([]byte("<style>\n"), content...), "\n</style>"...)
+	})
+
+	return page, nil
+}
+
+func downloadAsset(ctx context.Context, client *http.Client, assetURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, assetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("asset %s download: %w", assetURL, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("asset %s download: %s", assetURL, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,62 @@
+package dashboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusinikita/devex/db"
+	"github.com/rusinikita/devex/project"
+)
+
+func TestInlineAssets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echarts.js":
+			_, _ = w.Write([]byte(`var s = "</script>";`))
+		case "/pico.css":
+			_, _ = w.Write([]byte("body{}"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	page := `<head>
+    <script src="` + server.URL + `/echarts.js"></script>
+    <link href="` + server.URL + `/pico.css" rel="stylesheet">
+</head>`
+
+	result, err := inlineAssets(context.Background(), server.Client(), []byte(page))
+	require.NoError(t, err)
+
+	assert.Equal(t, `<head>
+    <script>
+var s = "<\/script>";
+</script>
+    <style>
+body{}
+</style>
+</head>`, string(result))
+
+	_, err = inlineAssets(context.Background(), server.Client(), []byte(`<script src="`+server.URL+`/missing.js"></script>`))
+	assert.Error(t, err)
+}
+
+func TestReportParams(t *testing.T) {
+	database := db.TestDB("file:report?mode=memory&cache=shared")
+
+	require.NoError(t, database.Create([]project.Project{{ID: 1, Alias: "one"}, {ID: 2, Alias: "two"}}).Error)
+
+	params, err := ReportParams(database, []string{"two"}, "?per_files=true&name_filter=*.go&project_ids=1")
+	require.NoError(t, err)
+
+	assert.Equal(t, Params{ProjectIDs: []project.ID{2}, PerFiles: true, NameFilter: "*.go"}, params)
+
+	_, err = ReportParams(database, []string{"two", "three"}, "")
+	assert.Error(t, err)
+}
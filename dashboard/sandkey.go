@@ -21,11 +21,16 @@ func sandkey(data values) components.Charter {
 	)
 
 	projects := map[string]float32{}
+	styles := map[string]*opts.ItemStyle{}
 
 	for _, v := range data {
 		p := path.Join(v.Alias, v.Package, v.Name)
 		nodeNames = append(nodeNames, v.Author, v.Alias, p)
 
+		if style := v.itemStyle(); style != nil {
+			styles[p] = style
+		}
+
 		projects[path.Join(v.Alias, v.Author)] += float32(v.Value)
 
 		links = append(links, opts.SankeyLink{
@@ -44,11 +49,16 @@ func sandkey(data values) components.Charter {
 	}
 
 	nodes := slices.Map(slices.Distinct(nodeNames), func(in string) opts.SankeyNode {
-		return opts.SankeyNode{
-			Name: in,
-			ItemStyle: &opts.ItemStyle{
+		style, ok := styles[in]
+		if !ok {
+			style = &opts.ItemStyle{
 				Color: colorful.FastWarmColor().Hex(),
-			},
+			}
+		}
+
+		return opts.SankeyNode{
+			Name:      in,
+			ItemStyle: style,
 		}
 	})
 
@@ -0,0 +1,257 @@
+package dashboard
+
+import (
+	"math"
+	"path"
+	"sort"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
+)
+
+// stabilityData is package coupling metrics by Robert C. Martin
+type stabilityData struct {
+	Alias   string  `json:"alias"`
+	Package string  `json:"package"`
+	Lines   float64 `json:"lines"`
+	// Afferent is count of packages importing package, Efferent is count of packages imported by package
+	Afferent int `json:"afferent"`
+	Efferent int `json:"efferent"`
+	// Instability is efferent share of package couplings, package without dependents is unstable
+	Instability float64 `json:"instability"`
+	// Types and Abstractness are declared types count and interfaces share, they are calculated for Go packages only
+	Types        float64 `json:"types"`
+	Abstractness float64 `json:"abstractness"`
+	// Distance is distance from main sequence, abstractness + instability = 1 line
+	Distance float64          `json:"distance"`
+	Priority project.Priority `json:"priority,omitempty"`
+}
+
+func (d stabilityData) label() string {
+	return valueData{Alias: d.Alias, Package: d.Package, Priority: d.Priority}.label()
+}
+
+// zoneDistance is distance from main sequence of packages in zones of pain and uselessness
+const zoneDistance = 0.5
+
+// zone returns "pain" for stable concrete packages which are hard to change
+// and "uselessness" for unstable abstract packages without dependents
+func (d stabilityData) zone() string {
+	switch {
+	case d.Types == 0 || d.Distance < zoneDistance:
+		return ""
+	case d.Abstractness+d.Instability < 1:
+		return "pain"
+	default:
+		return "uselessness"
+	}
+}
+
+type stabilities []stabilityData
+
+// stability calculates metrics of packages with imports or dependents.
+// Imports must not be trimmed, declarations are used for Go packages abstractness.
+func stability(all allImports, decls declarations) (result stabilities) {
+	round := func(f float64) float64 {
+		return math.Round(f*100) / 100
+	}
+
+	lines := map[string]float64{}
+	for _, data := range all {
+		lines[path.Join(data.Alias, data.Package)] += float64(data.Lines)
+	}
+
+	types := map[string]declarationsData{}
+	for _, d := range decls {
+		types[path.Join(d.Alias, d.Package)] = d
+	}
+
+	graphs := all.graphs()
+
+	aliases := make([]string, 0, len(graphs))
+	for alias := range graphs {
+		aliases = append(aliases, alias)
+	}
+
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		graph := graphs[alias]
+
+		afferent := map[string]int{}
+		for _, pkg := range graph.packages() {
+			for _, imported := range graph.imports(pkg) {
+				afferent[imported]++
+			}
+
+			if _, ok := afferent[pkg]; !ok {
+				afferent[pkg] = 0
+			}
+		}
+
+		for pkg, ca := range afferent {
+			d := stabilityData{
+				Alias:    alias,
+				Package:  pkg,
+				Lines:    lines[path.Join(alias, pkg)],
+				Afferent: ca,
+				Efferent: len(graph[pkg]),
+			}
+
+			d.Instability = round(float64(d.Efferent) / float64(d.Afferent+d.Efferent))
+
+			if t := types[path.Join(alias, pkg)]; t.Types > 0 {
+				d.Types = t.Types
+				d.Abstractness = round(t.Interfaces / t.Types)
+				d.Distance = round(math.Abs(d.Abstractness + d.Instability - 1))
+			}
+
+			result = append(result, d)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Distance != result[j].Distance {
+			return result[i].Distance > result[j].Distance
+		}
+
+		return result[i].label() < result[j].label()
+	})
+
+	return result
+}
+
+func (s stabilities) withPriorities(priorities map[string]project.Priority) stabilities {
+	for i := range s {
+		s[i].Priority = priorities[path.Join(s[i].Alias, s[i].Package)]
+	}
+
+	return s
+}
+
+func (s stabilities) withPackagesTrimmed(prefixes []string) stabilities {
+	for i := range s {
+		s[i].Package = slices.MultiTrimPrefix(s[i].Package, prefixes)
+	}
+
+	return s
+}
+
+func (s stabilities) table(title string) table {
+	t := table{
+		Title:   title,
+		Columns: []string{"Package", "Afferent", "Efferent", "Instability", "Abstractness", "Distance", "Zone"},
+	}
+
+	for _, d := range s {
+		abstractness, distance := "", ""
+		if d.Types > 0 {
+			abstractness, distance = formatFloat(d.Abstractness), formatFloat(d.Distance)
+		}
+
+		t.Rows = append(t.Rows, []string{
+			d.label(), formatFloat(float64(d.Afferent)), formatFloat(float64(d.Efferent)), formatFloat(d.Instability),
+			abstractness, distance, d.zone(),
+		})
+	}
+
+	return t
+}
+
+const stabilityChartID = "stability"
+
+// stabilityChart draws Go packages on abstractness and instability plane with main sequence line
+func stabilityChart(data stabilities) (components.Charter, error) {
+	rows := data
+	if len(rows) > 30 {
+		rows = rows[:30]
+	}
+
+	js, err := rows.table("Packages ordered by distance from main sequence").js(stabilityChartID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLines := 0.0
+	for _, d := range data {
+		maxLines = math.Max(maxLines, d.Lines)
+	}
+
+	var points []opts.ScatterData
+	for _, d := range data {
+		if d.Types == 0 {
+			continue
+		}
+
+		size := 8
+		if maxLines > 0 {
+			size += int(32 * d.Lines / maxLines)
+		}
+
+		points = append(points, opts.ScatterData{
+			Name:       d.label(),
+			Value:      []float64{d.Instability, d.Abstractness, d.Distance},
+			SymbolSize: size,
+		})
+	}
+
+	scatter := charts.NewScatter()
+	scatter.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: stabilityChartID,
+			Width:   "100%",
+			Height:  "600px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Package stability",
+			Subtitle: "Go packages far from main sequence: bottom left is zone of pain, top right is zone of uselessness. Size is lines",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true, Formatter: "{b}<br/>instability, abstractness, distance: {c}"}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Instability",
+			Type: "value",
+			Max:  1,
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Abstractness",
+			Type: "value",
+			Max:  1,
+		}),
+		charts.WithVisualMapOpts(opts.VisualMap{
+			Calculable: true,
+			Dimension:  "2",
+			Min:        0,
+			Max:        1,
+			InRange: &opts.VisualMapInRange{
+				Color: []string{"#a7d8de", "#eac736", "#d94e5d"},
+			},
+		}),
+		charts.WithGridOpts(opts.Grid{
+			ContainLabel: true,
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show:   true,
+			Orient: "horizontal",
+			Left:   "right",
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show: true, Title: "Save as image"},
+			},
+		}),
+	)
+
+	scatter.AddSeries("Packages", points,
+		charts.WithMarkLineNameCoordItemOpts(opts.MarkLineNameCoordItem{
+			Name:        "Main sequence",
+			Coordinate0: []interface{}{0, 1},
+			Coordinate1: []interface{}{1, 0},
+		}),
+	)
+	scatter.AddJSFuncs(js)
+
+	return scatter, nil
+}
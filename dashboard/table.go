@@ -0,0 +1,36 @@
+package dashboard
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+)
+
+//go:embed table.gohtml
+var tableTpl string
+
+// table is rendered under chart, page is able to render charts only
+type table struct {
+	Title   string
+	Columns []string
+	Rows    [][]string
+}
+
+// js returns chart JS function which inserts table after chart container with chartID
+func (t table) js(chartID string) (string, error) {
+	var html bytes.Buffer
+
+	err := template.Must(template.New("table").Parse(tableTpl)).Execute(&html, t)
+	if err != nil {
+		return "", err
+	}
+
+	// json string is valid js string literal, html symbols are escaped
+	literal, err := json.Marshal(html.String())
+	if err != nil {
+		return "", err
+	}
+
+	return "document.getElementById('" + chartID + "').parentElement.insertAdjacentHTML('afterend', " + string(literal) + ");", nil
+}
@@ -1,18 +1,33 @@
 package dashboard
 
 import (
+	"fmt"
+	"html/template"
+	"strings"
+
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/components"
 	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
 )
 
-func treeMap(data values) components.Charter {
+// treemapSubtitles are package colouring modes descriptions, priorities are coloured by default
+var treemapSubtitles = map[string]string{
+	"":                      "",
+	treemapColorTruckFactor: ". Red - truck factor 1, yellow - truck factor 2",
+	treemapColorOrphaned:    ". Redder packages have more changes by not active authors",
+	treemapColorComplexity:  ". Redder files have higher cognitive complexity",
+	treemapColorCoverage:    ". Redder files have less covered lines, grey files have no coverage report",
+}
+
+// treeMap draws files size, sizeName is size column name shown in tooltip, e.g. "Code lines"
+func treeMap(subtitle, sizeName string, data values) components.Charter {
 	tm := charts.NewTreeMap()
 
 	tm.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{
 			Title:    "File size chart",
-			Subtitle: "Project code lines count",
+			Subtitle: subtitle,
 		}),
 		charts.WithToolboxOpts(opts.Toolbox{
 			Show:   true,
@@ -27,7 +42,7 @@ func treeMap(data values) components.Charter {
 		}),
 		charts.WithTooltipOpts(opts.Tooltip{
 			Show:      true,
-			Formatter: opts.FuncOpts(toolTipFormatter),
+			Formatter: opts.FuncOpts(toolTipFormatter(sizeName)),
 		}),
 	)
 
@@ -67,17 +82,24 @@ func treeMap(data values) components.Charter {
 		},
 	)
 
-	maps := data.simpleMap()
-	tm.AddSeries("code", maps,
+	// AddSeries accepts opts.TreeMapNode only, it has no item style for colouring
+	series := charts.SingleSeries{Name: "code", Type: types.ChartTreeMap, Data: data.simpleMap()}
+	series.ConfigureSeriesOpts(
 		mapOpts,
 		charts.WithItemStyleOpts(opts.ItemStyle{BorderColor: "#fff"}),
 		charts.WithLabelOpts(opts.Label{Show: true, Position: "inside", Color: "White"}),
 	)
+	tm.MultiSeries = append(tm.MultiSeries, series)
 
 	return tm
 }
 
-const toolTipFormatter = `
+// toolTipFormatter returns treemap tooltip JS function showing size with sizeName units
+func toolTipFormatter(sizeName string) string {
+	return fmt.Sprintf(toolTipFormatterJS, template.JSEscapeString(strings.ToLower(sizeName)))
+}
+
+const toolTipFormatterJS = `
 function (info) {
 	var formatUtil = echarts.format;
 	var value = info.value;
@@ -86,8 +108,11 @@ function (info) {
 	for (var i = 1; i < treePathInfo.length; i++) {
 		treePath.push(treePathInfo[i].name);
 	}
-	return ['<div class="tooltip-title">' + formatUtil.encodeHTML(treePath.join('/')) + '</div>',
-		'Size: ' + formatUtil.addCommas(value) + ' lines',
-		].join('');
+	var lines = ['<div class="tooltip-title">' + formatUtil.encodeHTML(treePath.join('/')) + '</div>',
+		'Size: ' + formatUtil.addCommas(value) + ' %s'];
+	if (info.data && info.data.coverage !== undefined) {
+		lines.push('<br/>Coverage: ' + info.data.coverage + '%%');
+	}
+	return lines.join('');
 }
 `
@@ -2,7 +2,10 @@ package datacollector_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -120,6 +123,10 @@ func TestCollect(t *testing.T) {
 	assert.NoError(t, database.Find(&commits).Error)
 	assert.Len(t, commits, 30)
 
+	hashes, err := datacollector.CommitHashes(database, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"0": true, "1": true, "2": true}, map[string]bool(hashes))
+
 	var coverages []project.Coverage
 
 	assert.NoError(t, database.Find(&coverages).Error)
@@ -129,4 +136,340 @@ func TestCollect(t *testing.T) {
 	resultFiles = nil
 	assert.NoError(t, database.Find(&resultFiles).Error)
 	assert.Len(t, resultFiles, 30)
+
+	var jobs []project.DataFetchJob
+
+	assert.NoError(t, database.Find(&jobs).Error)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "files,coverage,git", jobs[0].DataSource)
+	assert.Equal(t, uint32(10), jobs[0].Files)
+	assert.Equal(t, uint32(3), jobs[0].Commits)
+	assert.True(t, jobs[0].Finished())
+	assert.Empty(t, jobs[0].Error)
+}
+
+func TestCollectUpdate(t *testing.T) {
+	ctx := context.TODO()
+	database := db.TestDB("file:update?mode=memory&cache=shared")
+
+	p := project.Project{
+		ID:         1,
+		Alias:      "test",
+		Language:   "go",
+		FolderPath: "test/test",
+	}
+
+	filesExtractor := func(names ...string) datasource.Extractor[files.File] {
+		return func(ctx context.Context, projectPath string, c chan<- files.File) error {
+			defer close(c)
+
+			for _, name := range names {
+				c <- files.File{
+					Package:    "pkg",
+					Name:       name,
+					Lines:      uint32(len(names)),
+					LineCounts: files.LineCounts{Code: 1},
+					Complexity: files.Complexity{
+						Cyclomatic:          uint32(len(names)),
+						FunctionsComplexity: []files.FunctionComplexity{{Name: "f", Cyclomatic: uint32(len(names))}},
+					},
+				}
+			}
+
+			return nil
+		}
+	}
+
+	require.NoError(t, datacollector.Collect(ctx, database, p, datasource.Extractors{
+		Files: filesExtractor("a.go", "b.go", "c.go"),
+	}))
+	require.NoError(t, datacollector.Collect(ctx, database, p, datasource.Extractors{
+		Files: filesExtractor("a.go", "d.go"),
+	}))
+
+	var resultFiles []project.File
+	require.NoError(t, database.Order("name").Find(&resultFiles, "project = ?", p.ID).Error)
+	require.Len(t, resultFiles, 4)
+
+	present := map[string]bool{}
+	for _, f := range resultFiles {
+		present[f.Name] = f.Present
+	}
+
+	assert.Equal(t, map[string]bool{"a.go": true, "b.go": false, "c.go": false, "d.go": true}, present)
+	assert.Equal(t, uint32(2), resultFiles[0].Lines)
+
+	var revisions []project.Revision
+	require.NoError(t, database.Find(&revisions, "project = ?", p.ID).Error)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, revisions[1].ID, resultFiles[0].Revision)
+
+	var snapshots []project.FileSnapshot
+	require.NoError(t, database.Find(&snapshots, "revision = ?", revisions[0].ID).Error)
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, uint32(1), snapshots[0].CodeLines)
+
+	// functions are replaced with the latest revision ones
+	var functions []project.Function
+	require.NoError(t, database.Find(&functions, "file = ?", resultFiles[0].ID).Error)
+	require.Len(t, functions, 1)
+	assert.Equal(t, uint32(2), functions[0].Complexity)
+	assert.Equal(t, uint32(2), resultFiles[0].Complexity)
+}
+
+func TestSetPriority(t *testing.T) {
+	database := db.TestDB("file:priority?mode=memory&cache=shared")
+
+	p := project.Project{ID: 1, Alias: "priority"}
+	require.NoError(t, database.Create(&p).Error)
+
+	for _, pkg := range []string{"billing", "billing/api", "billing/api/v1", "auth", "legacy/billing"} {
+		require.NoError(t, database.Create(&project.File{Project: p.ID, Package: pkg, Name: "main.go", Present: true}).Error)
+	}
+
+	updated, err := datacollector.SetPriority(database, p.Alias, project.Money, []string{"billing", "auth/*"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, updated)
+
+	updated, err = datacollector.SetPriority(database, p.Alias, project.Deprecated, []string{"legacy"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+
+	var packages []project.Package
+	require.NoError(t, database.Find(&packages, "project = ?", p.ID).Error)
+
+	priorities := map[string]project.Priority{}
+	for _, pkg := range packages {
+		priorities[pkg.Path] = pkg.Priority
+	}
+
+	assert.Equal(t, map[string]project.Priority{
+		"billing":        project.Money,
+		"billing/api":    project.Money,
+		"billing/api/v1": project.Money,
+		"auth":           project.Regular,
+		"legacy/billing": project.Deprecated,
+	}, priorities)
+}
+
+func TestSetAuthorAlias(t *testing.T) {
+	database := db.TestDB("file:authors?mode=memory&cache=shared")
+
+	aliases := func() map[string]string {
+		var list []project.AuthorAlias
+		require.NoError(t, database.Find(&list).Error)
+
+		result := map[string]string{}
+		for _, a := range list {
+			result[a.Author] = a.Canonical
+		}
+
+		return result
+	}
+
+	require.NoError(t, datacollector.SetAuthorAlias(database, "home@mail.com", "work@mail.com"))
+	require.NoError(t, datacollector.SetAuthorAlias(database, "old@mail.com", "home@mail.com"))
+	assert.Equal(t, map[string]string{"home@mail.com": "work@mail.com", "old@mail.com": "work@mail.com"}, aliases())
+
+	require.NoError(t, datacollector.SetAuthorAlias(database, "work@mail.com", "new@mail.com"))
+	assert.Equal(t, map[string]string{
+		"home@mail.com": "new@mail.com",
+		"old@mail.com":  "new@mail.com",
+		"work@mail.com": "new@mail.com",
+	}, aliases())
+
+	require.NoError(t, datacollector.SetAuthorAlias(database, "new@mail.com", "work@mail.com"))
+	assert.Equal(t, map[string]string{
+		"home@mail.com": "work@mail.com",
+		"old@mail.com":  "work@mail.com",
+		"new@mail.com":  "work@mail.com",
+	}, aliases())
+
+	require.NoError(t, datacollector.SetAuthorAlias(database, "old@mail.com", "old@mail.com"))
+	assert.Equal(t, map[string]string{"home@mail.com": "work@mail.com", "new@mail.com": "work@mail.com"}, aliases())
+}
+
+func TestSetTeams(t *testing.T) {
+	database := db.TestDB("file:teams?mode=memory&cache=shared")
+
+	members, err := datacollector.SetTeams(database, strings.NewReader(`
+backend:
+  - alice@company.com
+  - bob@company.com
+  - bob@company.com
+mobile:
+  - carol@company.com
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 3, members)
+
+	members, err = datacollector.SetTeams(database, strings.NewReader(`
+mobile: [carol@company.com, alice@company.com]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, members)
+
+	var list []project.TeamMember
+	require.NoError(t, database.Order("author").Find(&list).Error)
+	assert.Equal(t, []string{"alice@company.com", "carol@company.com"}, []string{list[0].Author, list[1].Author})
+	assert.Equal(t, "mobile", list[0].Team)
+
+	_, err = datacollector.SetTeams(database, strings.NewReader(`
+backend: [alice@company.com]
+mobile: [alice@company.com]
+`))
+	assert.Error(t, err)
+}
+
+func TestSetImportRules(t *testing.T) {
+	database := db.TestDB("file:rules?mode=memory&cache=shared")
+
+	require.NoError(t, database.Create(&project.Project{Alias: "rules"}).Error)
+
+	rules, err := datacollector.SetImportRules(database, "rules", strings.NewReader(`
+- package: domain/*
+  deny: [infra/*, api]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, rules)
+
+	p := project.Project{}
+	require.NoError(t, database.Take(&p, "alias = ?", "rules").Error)
+	assert.Equal(t, []project.ImportRule{{Package: "domain/*", Deny: []string{"infra/*", "api"}}}, p.Rules)
+	assert.Equal(t, "infra/*", p.Rules[0].Denied("domain/user", "infra/db/sql"))
+	assert.Empty(t, p.Rules[0].Denied("infra/db", "domain/user"))
+
+	_, err = datacollector.SetImportRules(database, "rules", strings.NewReader(`- package: "["
+  deny: [infra]`))
+	assert.Error(t, err)
+
+	_, err = datacollector.SetImportRules(database, "unknown", strings.NewReader(`[]`))
+	assert.Error(t, err)
+}
+
+func TestImportCoverage(t *testing.T) {
+	ctx := context.TODO()
+	database := db.TestDB("file:coverage?mode=memory&cache=shared")
+
+	root := t.TempDir()
+	report := filepath.Join(t.TempDir(), "lcov.info")
+	require.NoError(t, os.WriteFile(report, []byte("SF:"+filepath.Join(root, "src/app.ts")+"\nDA:1,1\nDA:2,0\nend_of_record\n"), 0o644))
+
+	p := project.Project{ID: 1, Alias: "coverage", FolderPath: root}
+	require.NoError(t, database.Create(&p).Error)
+	require.NoError(t, datacollector.Collect(ctx, database, p, datasource.Extractors{
+		Files: func(ctx context.Context, projectPath string, c chan<- files.File) error {
+			close(c)
+			return nil
+		},
+	}))
+
+	collected, err := datacollector.LatestRevision(database, p.ID)
+	require.NoError(t, err)
+
+	revision, err := datacollector.ImportCoverage(ctx, database, "coverage", report, "abc")
+	require.NoError(t, err)
+	assert.True(t, revision.CoverageOnly)
+	assert.Equal(t, "abc", revision.Hash)
+	assert.WithinDuration(t, time.Now(), revision.CreatedAt, time.Minute)
+
+	var coverages []project.Coverage
+	require.NoError(t, database.Find(&coverages, "revision = ?", revision.ID).Error)
+	require.Len(t, coverages, 1)
+	assert.Equal(t, uint8(50), coverages[0].Percent)
+	assert.Equal(t, []uint32{2}, coverages[0].UncoveredLines)
+
+	// lint reports are attached to collected revision
+	latest, err := datacollector.LatestRevision(database, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, collected, latest)
+
+	// back-filled report of collected commit has commit time
+	commitTime := time.Now().AddDate(0, -1, 0)
+	require.NoError(t, database.Create(&project.GitCommit{Hash: "def0123456", Time: commitTime}).Error)
+
+	backfilled, err := datacollector.ImportCoverage(ctx, database, "coverage", report, "def012")
+	require.NoError(t, err)
+	assert.WithinDuration(t, commitTime, backfilled.CreatedAt, time.Second)
+
+	// failed import keeps no snapshot
+	_, err = datacollector.ImportCoverage(ctx, database, "coverage", filepath.Join(root, "missing.info"), "")
+	assert.Error(t, err)
+
+	var revisions int64
+	require.NoError(t, database.Model(project.Revision{}).Where("project = ?", p.ID).Count(&revisions).Error)
+	assert.Equal(t, int64(3), revisions)
+}
+
+func TestImportLint(t *testing.T) {
+	database := db.TestDB("file:lint?mode=memory&cache=shared")
+
+	root := t.TempDir()
+	p := project.Project{ID: 1, Alias: "lint", FolderPath: root}
+	require.NoError(t, database.Create(&p).Error)
+	require.NoError(t, datacollector.Collect(context.TODO(), database, p, datasource.Extractors{
+		Files: func(ctx context.Context, projectPath string, c chan<- files.File) error {
+			defer close(c)
+			c <- files.File{Package: "db", Name: "create.go", Lines: 10}
+
+			return nil
+		},
+	}))
+
+	report := filepath.Join(t.TempDir(), "report.sarif")
+	require.NoError(t, os.WriteFile(report, []byte(`{"version": "2.1.0", "runs": [{
+		"tool": {"driver": {"name": "semgrep"}},
+		"results": [{
+			"ruleId": "go.lang.security.audit.sqli", "level": "error", "message": {"text": "sql injection"},
+			"locations": [{"physicalLocation": {"artifactLocation": {"uri": "file://`+filepath.ToSlash(root)+`/db/create.go"}, "region": {"startLine": 7, "startColumn": 3}}}]
+		}]
+	}]}`), 0o644))
+
+	require.NoError(t, datacollector.ImportLint(database, "lint", report))
+
+	var lintErrors []project.LintError
+	require.NoError(t, database.Find(&lintErrors).Error)
+	require.Len(t, lintErrors, 1)
+	assert.Equal(t, "sql injection", lintErrors[0].Message)
+	assert.Equal(t, "error", lintErrors[0].Severity)
+	assert.Equal(t, "semgrep", lintErrors[0].Source)
+	assert.Equal(t, "go.lang.security.audit.sqli", lintErrors[0].Rule)
+	assert.Equal(t, uint(7), lintErrors[0].FileLine)
+	assert.Equal(t, uint(3), lintErrors[0].FileColumn)
+}
+
+func TestCreateProject(t *testing.T) {
+	database := db.TestDB("file:create?mode=memory&cache=shared")
+
+	p := project.Project{Alias: "create", FolderPath: "old"}
+	require.NoError(t, datacollector.CreateProject(database, &p))
+	assert.NotZero(t, p.ID)
+
+	err := datacollector.CreateProject(database, &project.Project{Alias: "create", FolderPath: "new"})
+	assert.ErrorIs(t, err, datacollector.ErrProjectExists)
+
+	var projects []project.Project
+	require.NoError(t, database.Find(&projects, "alias = ?", "create").Error)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "old", projects[0].FolderPath)
+}
+
+func TestFailInterruptedJobs(t *testing.T) {
+	database := db.TestDB("file:interrupted?mode=memory&cache=shared")
+
+	finishedAt := time.Now()
+	jobs := []project.DataFetchJob{
+		{Project: 1},
+		{Project: 1, FinishedAt: &finishedAt},
+	}
+	require.NoError(t, database.Create(&jobs).Error)
+
+	require.NoError(t, datacollector.FailInterruptedJobs(database))
+
+	var result []project.DataFetchJob
+	require.NoError(t, database.Order("id").Find(&result).Error)
+	require.Len(t, result, 2)
+	assert.True(t, result[0].Finished())
+	assert.Equal(t, datacollector.ErrJobInterrupted.Error(), result[0].Error)
+	assert.Empty(t, result[1].Error)
 }
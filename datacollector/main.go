@@ -4,10 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
 	"gorm.io/gorm"
 
 	"github.com/rusinikita/devex/datasource"
@@ -16,152 +22,669 @@ import (
 	"github.com/rusinikita/devex/datasource/lint"
 	"github.com/rusinikita/devex/datasource/testcoverage"
 	"github.com/rusinikita/devex/project"
+	"github.com/rusinikita/devex/slices"
 )
 
 // DepWheel chart
 // go list -json="ImportPath,Imports" ./...
 
-func Collect(ctx context.Context, db *gorm.DB, pkt project.Project, extractors datasource.Extractors) error {
-	group, _ := errgroup.WithContext(ctx)
+// Collect runs project data collection and records it as job.
+func Collect(ctx context.Context, db *gorm.DB, pkt project.Project, extractors datasource.Extractors) (err error) {
+	job := &project.DataFetchJob{
+		Project:    pkt.ID,
+		DataSource: strings.Join(extractors.Names(), ","),
+	}
+
+	err = db.Create(job).Error
+	if err != nil {
+		return fmt.Errorf("job creating: %q", err)
+	}
+
+	defer func() {
+		finishJob(db, job, err)
+	}()
+
+	revision, err := createRevision(db, pkt)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Start files data collection, revision", revision.Hash)
+
+	err = collectFiles(ctx, db, pkt, revision.ID, job, extractors.Files)
+	if err != nil {
+		return err
+	}
+
+	err = syncPackages(db, pkt.ID)
+	if err != nil {
+		return err
+	}
+
+	if extractors.Coverage != nil {
+		log.Println("Start coverage data collection")
+
+		err := collectCoverage(ctx, db, pkt, revision.ID, extractors.Coverage)
+		if err != nil {
+			log.Printf("skip coverage collection: %q\n", err)
+		}
+	}
+
+	if extractors.Git != nil {
+		log.Println("Start git data collection")
+
+		err := collectGit(ctx, db, pkt, job, extractors.Git)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Println("Done. Finishing")
+
+	return nil
+}
+
+// ErrProjectExists is returned on creating project with existing alias, project data is updated by Update
+var ErrProjectExists = errors.New("project already exists, use update")
+
+// CreateProject saves new project, project with the same alias must not exist
+func CreateProject(db *gorm.DB, pkt *project.Project) error {
+	var count int64
+
+	err := db.Model(project.Project{}).Where("alias = ?", pkt.Alias).Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%s: %w", pkt.Alias, ErrProjectExists)
+	}
+
+	return db.Create(pkt).Error
+}
+
+// Update collects project data changes since the last collection
+func Update(ctx context.Context, db *gorm.DB, pkt project.Project) error {
+	known, err := CommitHashes(db, pkt.ID)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Updating project in", pkt.FolderPath, "skipping", len(known), "known commits")
+
+	extractors := datasource.NewExtractors(pkt)
+	extractors.Git = git.ExtractCommitsExcept(known)
+
+	return Collect(ctx, db, pkt, extractors)
+}
+
+func saveProgress(db *gorm.DB, job *project.DataFetchJob) {
+	err := db.Model(job).Select("files", "commits").Updates(job).Error
+	if err != nil {
+		log.Println("job progress saving:", err)
+	}
+}
+
+// ErrJobInterrupted is error of jobs that were not finished before application stop
+var ErrJobInterrupted = errors.New("interrupted")
+
+// FailInterruptedJobs marks unfinished jobs as failed, it must be called on start before any job is started
+func FailInterruptedJobs(db *gorm.DB) error {
+	finishedAt := time.Now()
+
+	err := db.Model(project.DataFetchJob{}).
+		Where("finished_at is null").
+		Updates(project.DataFetchJob{FinishedAt: &finishedAt, Error: ErrJobInterrupted.Error()}).
+		Error
+	if err != nil {
+		return fmt.Errorf("interrupted jobs failing: %w", err)
+	}
+
+	return nil
+}
+
+func finishJob(db *gorm.DB, job *project.DataFetchJob, jobErr error) {
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+
+	if jobErr != nil {
+		job.Error = jobErr.Error()
+	}
+
+	err := db.Save(job).Error
+	if err != nil {
+		log.Println("job saving:", err)
+	}
+}
+
+// CommitHashes returns hashes of project commits stored in database
+func CommitHashes(db *gorm.DB, projectID project.ID) (slices.Set[string], error) {
+	var hashes []string
+
+	err := db.Model(project.GitCommit{}).
+		Distinct("git_commits.hash").
+		Joins("join git_changes ch on ch.'commit' = git_commits.id").
+		Joins("join files f on f.id = ch.file").
+		Where("f.project = ?", projectID).
+		Scan(&hashes).
+		Error
+
+	return slices.ToSet(hashes), err
+}
 
-	log.Println("Start files data collection")
+// LatestRevision returns the newest project data collection revision id, coverage imports are skipped.
+// It is zero if project data was collected without revisions.
+func LatestRevision(db *gorm.DB, projectID project.ID) (id project.ID, err error) {
+	err = db.Model(project.Revision{}).
+		Select("id").
+		Where("project = ? and not coverage_only", projectID).
+		Order("created_at desc, id desc").
+		Limit(1).
+		Scan(&id).
+		Error
+
+	return id, err
+}
+
+func createRevision(db *gorm.DB, pkt project.Project) (project.Revision, error) {
+	hash, err := git.HeadHash(pkt.FolderPath)
+	if err != nil {
+		log.Printf("revision without commit hash: %q\n", err)
+	}
+
+	revision := project.Revision{
+		Project: pkt.ID,
+		Hash:    hash,
+	}
+
+	err = db.Create(&revision).Error
+	if err != nil {
+		return revision, fmt.Errorf("revision creating: %q", err)
+	}
+
+	return revision, nil
+}
+
+// collectFiles saves walked files and marks files that are gone as not present.
+// Files data is also saved as revision snapshot.
+func collectFiles(ctx context.Context, db *gorm.DB, pkt project.Project, revision project.ID, job *project.DataFetchJob, extractor datasource.Extractor[files.File]) error {
+	err := db.Model(project.File{}).Where("project = ?", pkt.ID).Update("present", false).Error
+	if err != nil {
+		return fmt.Errorf("files presence reset: %q", err)
+	}
+
+	group, _ := errgroup.WithContext(ctx)
 
 	fileChan := make(chan files.File)
 	group.Go(func() error {
 		for file := range fileChan {
-			err := db.Create(&project.File{
+			projectFile := project.File{
 				Package: file.Package,
 				Name:    file.Name,
 				Project: pkt.ID,
-				Lines:   file.Lines,
-				Symbols: file.Symbols,
-				Tags:    file.Tags,
-				Imports: file.Imports,
-				Present: true,
-			}).Error
+			}
+			err := db.FirstOrCreate(&projectFile, projectFile).Error
+			if err != nil {
+				return fmt.Errorf("finding file: %q", err)
+			}
+
+			err = db.Model(&projectFile).
+				Select("revision", "language", "lines", "code_lines", "comment_lines", "blank_lines",
+					"package_name", "functions", "exported_functions", "exported_types", "types", "interfaces", "function_lines", "max_function_lines",
+					"complexity", "cognitive_complexity",
+					"symbols", "tags", "imports", "present").
+				Updates(project.File{
+					Revision:     revision,
+					Language:     file.Language,
+					Lines:        file.Lines,
+					CodeLines:    file.Code,
+					CommentLines: file.Comment,
+					BlankLines:   file.Blank,
+
+					PackageName:       file.PackageName,
+					Functions:         file.Functions,
+					ExportedFunctions: file.ExportedFunctions,
+					ExportedTypes:     file.ExportedTypes,
+					Types:             file.Types,
+					Interfaces:        file.Interfaces,
+					FunctionLines:     file.FunctionLines,
+					MaxFunctionLines:  file.MaxFunctionLines,
+
+					Complexity:          file.Cyclomatic,
+					CognitiveComplexity: file.Cognitive,
+
+					Symbols: file.Symbols,
+					Tags:    file.Tags,
+					Imports: file.Imports,
+					Present: true,
+				}).Error
 			if err != nil {
 				return fmt.Errorf("file saving: %q", err)
 			}
+
+			err = saveFunctions(db, projectFile.ID, file.FunctionsComplexity)
+			if err != nil {
+				return err
+			}
+
+			err = db.Create(&project.FileSnapshot{
+				Revision:  revision,
+				File:      projectFile.ID,
+				Lines:     file.Lines,
+				CodeLines: file.Code,
+				Symbols:   file.Symbols,
+				Tags:      file.Tags,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("file snapshot saving: %q", err)
+			}
+
+			job.Files++
+			if job.Files%100 == 0 {
+				saveProgress(db, job)
+			}
 		}
 
 		return nil
 	})
 
-	err := extractors.Files(ctx, pkt.FolderPath, fileChan)
+	err = extractor(ctx, pkt.FolderPath, fileChan)
 	if err != nil {
 		return fmt.Errorf("files collection: %q", err)
 	}
 
-	if extractors.Coverage != nil {
-		c := make(chan testcoverage.Package)
-
-		group.Go(func() error {
-			for pkg := range c {
-				for _, file := range pkg.Files {
-					projectFile := project.File{
-						Name:    file.File,
-						Package: pkg.Path,
-						Project: pkt.ID,
-					}
-					tx := db.FirstOrCreate(&projectFile, projectFile)
-					err := tx.Error
-					if err != nil {
-						return fmt.Errorf("finding coverage file: %q", err)
-					}
-
-					err = db.Create(&project.Coverage{
-						File:           projectFile.ID,
-						Percent:        file.Percent,
-						UncoveredCount: uint32(len(file.UncoveredLines)),
-						UncoveredLines: file.UncoveredLines,
-					}).Error
-					if err != nil {
-						return fmt.Errorf("commit saving: %q", err)
-					}
-				}
-			}
+	return group.Wait()
+}
 
-			return nil
-		})
+// saveFunctions replaces file functions with the latest revision ones
+func saveFunctions(db *gorm.DB, file project.ID, functions []files.FunctionComplexity) error {
+	err := db.Where("file = ?", file).Delete(&project.Function{}).Error
+	if err != nil {
+		return fmt.Errorf("functions deleting: %q", err)
+	}
 
-		log.Println("Start coverage data collection")
+	if len(functions) == 0 {
+		return nil
+	}
 
-		err := extractors.Coverage(ctx, pkt.FolderPath, c)
-		if err != nil {
-			log.Printf("skip coverage collection: %q\n", err)
+	err = db.Create(slices.Map(functions, func(f files.FunctionComplexity) project.Function {
+		return project.Function{
+			File:       file,
+			Name:       f.Name,
+			Line:       f.Line,
+			Lines:      f.Lines,
+			Complexity: f.Cyclomatic,
+			Cognitive:  f.Cognitive,
 		}
+	})).Error
+	if err != nil {
+		return fmt.Errorf("functions saving: %q", err)
 	}
 
-	if extractors.Git != nil {
-		c := make(chan git.Commit)
+	return nil
+}
 
-		group.Go(func() error {
-			commitsHandled := 0
+func collectCoverage(ctx context.Context, db *gorm.DB, pkt project.Project, revision project.ID, extractor datasource.Extractor[testcoverage.Package]) error {
+	group, _ := errgroup.WithContext(ctx)
 
-			for commit := range c {
-				commitsHandled++
-				if commitsHandled%100 == 0 {
-					log.Println(commitsHandled, "commits handled")
+	c := make(chan testcoverage.Package)
+	group.Go(func() error {
+		for pkg := range c {
+			for _, file := range pkg.Files {
+				projectFile := project.File{
+					Name:    file.File,
+					Package: pkg.Path,
+					Project: pkt.ID,
+				}
+				tx := db.FirstOrCreate(&projectFile, projectFile)
+				err := tx.Error
+				if err != nil {
+					return fmt.Errorf("finding coverage file: %q", err)
 				}
 
-				c := project.GitCommit{
-					Hash:    commit.Hash,
-					Author:  commit.Author,
-					Message: commit.Message,
-					Time:    commit.Time,
+				err = db.Create(&project.Coverage{
+					File:              projectFile.ID,
+					Revision:          revision,
+					Percent:           file.Percent,
+					UncoveredCount:    uint32(len(file.UncoveredLines)),
+					UncoveredLines:    file.UncoveredLines,
+					Statements:        file.Statements,
+					CoveredStatements: file.CoveredStatements,
+					UncoveredRanges: slices.Map(file.UncoveredRanges, func(r testcoverage.LineRange) [2]uint32 {
+						return [2]uint32{r.From, r.To}
+					}),
+				}).Error
+				if err != nil {
+					return fmt.Errorf("commit saving: %q", err)
 				}
-				err := db.FirstOrCreate(&c, c).Error
+			}
+		}
+
+		return nil
+	})
+
+	extractErr := extractor(ctx, pkt.FolderPath, c)
+
+	err := group.Wait()
+	if extractErr != nil {
+		return extractErr
+	}
+
+	return err
+}
+
+// ImportCoverage appends project coverage snapshot read from report, hash is optional report commit hash
+func ImportCoverage(ctx context.Context, db *gorm.DB, alias, report, hash string) (revision project.Revision, err error) {
+	pkt := project.Project{}
+	if err = db.Take(&pkt, "alias = ?", alias).Error; err != nil {
+		return revision, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		revision = project.Revision{
+			Project:      pkt.ID,
+			Hash:         hash,
+			CoverageOnly: true,
+		}
+
+		// back-filled reports of collected commits are placed on commit time
+		if hash != "" {
+			var commits []project.GitCommit
+
+			err := tx.Where("hash like ?", hash+"%").Order("time desc").Limit(1).Find(&commits).Error
+			if err != nil {
+				return fmt.Errorf("finding commit: %q", err)
+			}
+
+			if len(commits) > 0 {
+				revision.CreatedAt = commits[0].Time
+			}
+		}
+
+		if err := tx.Create(&revision).Error; err != nil {
+			return fmt.Errorf("revision creating: %q", err)
+		}
+
+		return collectCoverage(ctx, tx, pkt, revision.ID, testcoverage.ExtractReports([]string{report}))
+	})
+
+	return revision, err
+}
+
+func collectGit(ctx context.Context, db *gorm.DB, pkt project.Project, job *project.DataFetchJob, extractor datasource.Extractor[git.Commit]) error {
+	group, _ := errgroup.WithContext(ctx)
+
+	c := make(chan git.Commit)
+	group.Go(func() error {
+		for commit := range c {
+			job.Commits++
+			if job.Commits%100 == 0 {
+				log.Println(job.Commits, "commits handled")
+				saveProgress(db, job)
+			}
+
+			c := project.GitCommit{
+				Hash:    commit.Hash,
+				Author:  commit.Author,
+				Message: commit.Message,
+				Time:    commit.Time,
+			}
+			err := db.FirstOrCreate(&c, c).Error
+			if err != nil {
+				return fmt.Errorf("finding commit: %q", err)
+			}
+
+			for _, cFile := range commit.Files {
+				file := project.File{
+					Name:    cFile.File,
+					Package: cFile.Package,
+					Project: pkt.ID,
+				}
+				err := db.FirstOrCreate(&file, file).Error
 				if err != nil {
-					return fmt.Errorf("finding commit: %q", err)
+					return fmt.Errorf("finding commit file: %q", err)
 				}
 
-				for _, cFile := range commit.Files {
-					file := project.File{
-						Name:    cFile.File,
-						Package: cFile.Package,
-						Project: pkt.ID,
-					}
-					err := db.FirstOrCreate(&file, file).Error
-					if err != nil {
-						return fmt.Errorf("finding commit file: %q", err)
-					}
-
-					err = db.Create(&project.GitChange{
-						File:        file.ID,
-						Commit:      c.ID,
-						RowsAdded:   cFile.RowsAdded,
-						RowsRemoved: cFile.RowsRemoved,
-						Time:        commit.Time,
-					}).Error
-					if err != nil {
-						return fmt.Errorf("commit saving: %q", err)
-					}
+				err = db.Create(&project.GitChange{
+					File:        file.ID,
+					Commit:      c.ID,
+					RowsAdded:   cFile.RowsAdded,
+					RowsRemoved: cFile.RowsRemoved,
+					Time:        commit.Time,
+				}).Error
+				if err != nil {
+					return fmt.Errorf("commit saving: %q", err)
 				}
 			}
+		}
+
+		return nil
+	})
 
-			return nil
-		})
+	err := extractor(ctx, pkt.FolderPath, c)
+	if err != nil {
+		return fmt.Errorf("git commits collection: %q", err)
+	}
 
-		log.Println("Start git data collection")
+	return group.Wait()
+}
+
+// syncPackages creates packages of project files and updates packages presence.
+// Packages priorities are kept.
+func syncPackages(db *gorm.DB, projectID project.ID) error {
+	var packages []project.Package
+	err := db.Model(project.File{}).
+		Select("package as path", "max(present) as present").
+		Where("project = ?", projectID).
+		Group("package").
+		Scan(&packages).
+		Error
+	if err != nil {
+		return fmt.Errorf("files packages: %q", err)
+	}
+
+	for _, p := range packages {
+		pkg := project.Package{
+			Project: projectID,
+			Path:    p.Path,
+		}
+
+		err = db.Where("project = ? and path = ?", projectID, p.Path).FirstOrCreate(&pkg).Error
+		if err != nil {
+			return fmt.Errorf("finding package: %q", err)
+		}
 
-		err := extractors.Git(ctx, pkt.FolderPath, c)
+		err = db.Model(&pkg).Update("present", p.Present).Error
 		if err != nil {
-			return fmt.Errorf("git commits collection: %q", err)
+			return fmt.Errorf("package saving: %q", err)
 		}
 	}
 
-	log.Println("Done. Finishing")
+	return nil
+}
 
-	return group.Wait()
+// SetPriority sets priority of project packages matching any of globs.
+// Glob matches package and all its subpackages.
+func SetPriority(database *gorm.DB, projectAlias string, priority project.Priority, globs []string) (updated int, err error) {
+	projectId, err := getProjectIdByAlias(database, projectAlias)
+	if err != nil {
+		return 0, err
+	}
+
+	err = syncPackages(database, projectId)
+	if err != nil {
+		return 0, err
+	}
+
+	var packages []project.Package
+	err = database.Find(&packages, "project = ?", projectId).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []project.ID
+	for _, p := range packages {
+		for _, glob := range globs {
+			if p.Match(glob) {
+				ids = append(ids, p.ID)
+				break
+			}
+		}
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	err = database.Model(project.Package{}).Where("id in ?", ids).Update("priority", priority).Error
+
+	return len(ids), err
+}
+
+// SetAuthorAlias merges author into canonical author, aliases of author are moved to canonical too.
+// Alias is removed if author and canonical are the same.
+func SetAuthorAlias(database *gorm.DB, author, canonical string) error {
+	if author == "" || canonical == "" {
+		return errors.New("author and canonical author are required")
+	}
+
+	return database.Transaction(func(tx *gorm.DB) error {
+		err := tx.Delete(project.AuthorAlias{}, "author = ?", author).Error
+		if err != nil || author == canonical {
+			return err
+		}
+
+		// canonical can be alias itself
+		alias := project.AuthorAlias{}
+		err = tx.Limit(1).Find(&alias, "author = ?", canonical).Error
+		if err != nil {
+			return err
+		}
+
+		switch alias.Canonical {
+		case "":
+		case author:
+			// reverse merge, canonical stops being alias
+			err = tx.Delete(&alias).Error
+		default:
+			canonical = alias.Canonical
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(project.AuthorAlias{}).Where("canonical = ?", author).Update("canonical", canonical).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&project.AuthorAlias{Author: author, Canonical: canonical}).Error
+	})
+}
+
+// SetTeams replaces teams with YAML config of team names and author emails lists:
+//
+//	backend:
+//	  - alice@company.com
+//	mobile:
+//	  - bob@company.com
+func SetTeams(database *gorm.DB, config io.Reader) (members int, err error) {
+	teams := map[string][]string{}
+	if err = yaml.NewDecoder(config).Decode(&teams); err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("teams config: %w", err)
+	}
+
+	var list []project.TeamMember
+	authorTeams := map[string]string{}
+
+	for team, authors := range teams {
+		for _, author := range authors {
+			if other, ok := authorTeams[author]; ok {
+				if other != team {
+					return 0, fmt.Errorf("teams config: %s is in %s and %s teams", author, other, team)
+				}
+
+				continue
+			}
+
+			authorTeams[author] = team
+			list = append(list, project.TeamMember{Team: team, Author: author})
+		}
+	}
+
+	err = database.Transaction(func(tx *gorm.DB) error {
+		err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(project.TeamMember{}).Error
+		if err != nil || len(list) == 0 {
+			return err
+		}
+
+		return tx.Create(&list).Error
+	})
+
+	return len(list), err
+}
+
+// SetImportRules replaces project layering rules with YAML config of rules list,
+// e.g. "[{package: domain/*, deny: [infra/*]}]"
+func SetImportRules(database *gorm.DB, alias string, config io.Reader) (rules int, err error) {
+	var list []project.ImportRule
+	if err = yaml.NewDecoder(config).Decode(&list); err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("rules config: %w", err)
+	}
+
+	for _, rule := range list {
+		if rule.Package == "" || len(rule.Deny) == 0 {
+			return 0, fmt.Errorf("rules config: package and deny globs are required")
+		}
+
+		for _, glob := range append([]string{rule.Package}, rule.Deny...) {
+			if _, err := path.Match(glob, ""); err != nil {
+				return 0, fmt.Errorf("rules config: %s glob: %w", glob, err)
+			}
+		}
+	}
+
+	result := database.Model(project.Project{}).Where("alias = ?", alias).
+		Select("rules").Updates(project.Project{Rules: list})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf("project %s not found", alias)
+	}
+
+	return len(list), nil
 }
 
 func CheckStyle(database *gorm.DB, projectAlias string, filePath string) error {
-	projectId, err := getProjectIdByAlias(database, projectAlias)
+	return importLint(database, projectAlias, filePath, lint.ExtractCheckStyleXml)
+}
+
+// ImportSarif saves SARIF report findings as lint errors of the latest project revision
+func ImportSarif(database *gorm.DB, projectAlias string, filePath string) error {
+	return importLint(database, projectAlias, filePath, lint.ExtractSarif)
+}
+
+// ImportLint saves checkstyle XML or SARIF report detected by content
+func ImportLint(database *gorm.DB, projectAlias string, filePath string) error {
+	return importLint(database, projectAlias, filePath, lint.Extract)
+}
+
+func importLint(database *gorm.DB, projectAlias string, filePath string, extract func(io.Reader) ([]lint.LinterFile, error)) error {
+	var pkt project.Project
+	err := database.Select("id", "folder_path").Where("alias = ?", projectAlias).Take(&pkt).Error
 	if err != nil {
 		return err
 	}
 
-	log.Printf("project found, id: %d \n", projectId)
+	log.Printf("project found, id: %d \n", pkt.ID)
+
+	revision, err := LatestRevision(database, pkt.ID)
+	if err != nil {
+		return err
+	}
 
 	file, err := os.Open(filePath)
 
@@ -171,14 +694,30 @@ func CheckStyle(database *gorm.DB, projectAlias string, filePath string) error {
 
 	defer file.Close()
 
-	lintFiles, err := lint.ExtractCheckStyleXml(file)
+	lintFiles, err := extract(file)
 
 	if err != nil {
 		return err
 	}
 	log.Printf("count files in report: %d \n", len(lintFiles))
 
-	return batchRows(database, projectId, lintFiles)
+	// SARIF tools often report absolute file paths
+	root, err := filepath.Abs(pkt.FolderPath)
+	if err != nil {
+		return err
+	}
+
+	for i, f := range lintFiles {
+		if !filepath.IsAbs(f.Path) {
+			continue
+		}
+
+		if rel, err := filepath.Rel(root, f.Path); err == nil {
+			lintFiles[i].Path = filepath.ToSlash(rel)
+		}
+	}
+
+	return batchRows(database, pkt.ID, revision, lintFiles)
 }
 
 func getProjectIdByAlias(database *gorm.DB, alias string) (project.ID, error) {
@@ -188,7 +727,7 @@ func getProjectIdByAlias(database *gorm.DB, alias string) (project.ID, error) {
 	return projectDao.ID, tx.Error
 }
 
-func batchRows(database *gorm.DB, projectId project.ID, lintFiles []lint.LinterFile) error {
+func batchRows(database *gorm.DB, projectId, revision project.ID, lintFiles []lint.LinterFile) error {
 	return database.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Error; err != nil {
 			return err
@@ -202,9 +741,9 @@ func batchRows(database *gorm.DB, projectId project.ID, lintFiles []lint.LinterF
 			fileIds = append(fileIds, file.ID)
 		}
 
-		tx.Where("file_id IN ?", fileIds).Delete(&project.LintError{})
+		tx.Where("file_id IN ? and revision = ?", fileIds, revision).Delete(&project.LintError{})
 
-		lintErrors, err := convertDtoToDao(lintFiles, filesFromDb)
+		lintErrors, err := convertDtoToDao(lintFiles, filesFromDb, revision)
 		if err != nil {
 			return err
 		}
@@ -217,7 +756,7 @@ func batchRows(database *gorm.DB, projectId project.ID, lintFiles []lint.LinterF
 	})
 }
 
-func convertDtoToDao(lintFiles []lint.LinterFile, fileDaoList []project.File) ([]project.LintError, error) {
+func convertDtoToDao(lintFiles []lint.LinterFile, fileDaoList []project.File, revision project.ID) ([]project.LintError, error) {
 	var result []project.LintError
 
 	for _, lintFile := range lintFiles {
@@ -229,11 +768,13 @@ func convertDtoToDao(lintFiles []lint.LinterFile, fileDaoList []project.File) ([
 		for _, lintError := range lintFile.Errors {
 			result = append(result, project.LintError{
 				FileId:     fileId,
+				Revision:   revision,
 				FileColumn: lintError.Column,
 				FileLine:   lintError.Line,
 				Message:    lintError.Message,
 				Severity:   lintError.Severity,
 				Source:     lintError.Source,
+				Rule:       lintError.Rule,
 			})
 		}
 	}
@@ -0,0 +1,269 @@
+package files
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// FunctionComplexity is function cyclomatic and cognitive complexity
+type FunctionComplexity struct {
+	Name  string // function name, methods are prefixed with receiver type, e.g. "Store.Get"
+	Line  uint32
+	Lines uint32
+	// Cyclomatic is 1 + branches and boolean operators count
+	Cyclomatic uint32
+	// Cognitive is branches count weighted by nesting level, boolean operators sequences add 1
+	Cognitive uint32
+}
+
+// Complexity is file complexity totals, functions are filled for Go files only
+type Complexity struct {
+	Cyclomatic          uint32
+	Cognitive           uint32
+	FunctionsComplexity []FunctionComplexity
+}
+
+func complexityTotals(functions []FunctionComplexity) Complexity {
+	c := Complexity{FunctionsComplexity: functions}
+	for _, f := range functions {
+		c.Cyclomatic += f.Cyclomatic
+		c.Cognitive += f.Cognitive
+	}
+
+	return c
+}
+
+// goFunctionsComplexity returns complexity of file functions and methods
+func goFunctionsComplexity(fset *token.FileSet, f *ast.File) (functions []FunctionComplexity) {
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		name := fn.Name.Name
+		if recv := receiverName(fn); recv != "" {
+			name = recv + "." + name
+		}
+
+		start, end := fset.Position(fn.Pos()).Line, fset.Position(fn.End()).Line
+
+		functions = append(functions, FunctionComplexity{
+			Name:       name,
+			Line:       uint32(start),
+			Lines:      uint32(end - start + 1),
+			Cyclomatic: goCyclomatic(fn.Body),
+			Cognitive:  goCognitive(fn.Body, 0),
+		})
+	}
+
+	return functions
+}
+
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+
+	typ := fn.Recv.List[0].Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+func goCyclomatic(body ast.Node) uint32 {
+	complexity := uint32(1)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+
+		return true
+	})
+
+	return complexity
+}
+
+// goCognitive walks statements and adds nesting level to each nested branch, function literals increase nesting
+func goCognitive(node ast.Node, nesting uint32) (complexity uint32) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == node {
+			return true
+		}
+
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			complexity += goIfCognitive(n, nesting, nesting)
+
+			return false
+		case *ast.ForStmt:
+			complexity += 1 + nesting + goConditionComplexity(n.Cond)
+			complexity += goCognitive(n.Body, nesting+1)
+
+			return false
+		case *ast.RangeStmt:
+			complexity += 1 + nesting
+			complexity += goCognitive(n.Body, nesting+1)
+
+			return false
+		case *ast.SwitchStmt:
+			complexity += 1 + nesting
+			complexity += goCognitive(n.Body, nesting+1)
+
+			return false
+		case *ast.TypeSwitchStmt:
+			complexity += 1 + nesting
+			complexity += goCognitive(n.Body, nesting+1)
+
+			return false
+		case *ast.SelectStmt:
+			complexity += 1 + nesting
+			complexity += goCognitive(n.Body, nesting+1)
+
+			return false
+		case *ast.FuncLit:
+			complexity += goCognitive(n.Body, nesting+1)
+
+			return false
+		case *ast.BranchStmt:
+			if n.Label != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			complexity += goConditionComplexity(n)
+
+			return false
+		}
+
+		return true
+	})
+
+	return complexity
+}
+
+// goIfCognitive returns if statement complexity, else if branch has no nesting increment, else branch adds 1
+func goIfCognitive(n *ast.IfStmt, nesting, increment uint32) (complexity uint32) {
+	complexity = 1 + increment + goConditionComplexity(n.Cond)
+
+	if n.Init != nil {
+		complexity += goCognitive(n.Init, nesting)
+	}
+
+	complexity += goCognitive(n.Body, nesting+1)
+
+	switch e := n.Else.(type) {
+	case *ast.IfStmt:
+		complexity += goIfCognitive(e, nesting, 0)
+	case *ast.BlockStmt:
+		complexity += 1 + goCognitive(e, nesting+1)
+	}
+
+	return complexity
+}
+
+// goConditionComplexity adds 1 for each sequence of the same boolean operators, "a && b && c || d" is 2
+func goConditionComplexity(cond ast.Expr) (complexity uint32) {
+	var operators []token.Token
+
+	var walk func(e ast.Expr)
+	walk = func(e ast.Expr) {
+		switch e := e.(type) {
+		case *ast.ParenExpr:
+			walk(e.X)
+		case *ast.UnaryExpr:
+			walk(e.X)
+		case *ast.BinaryExpr:
+			walk(e.X)
+			if e.Op == token.LAND || e.Op == token.LOR {
+				operators = append(operators, e.Op)
+			}
+			walk(e.Y)
+		}
+	}
+
+	if cond != nil {
+		walk(cond)
+	}
+
+	for i, op := range operators {
+		if i == 0 || operators[i-1] != op {
+			complexity++
+		}
+	}
+
+	return complexity
+}
+
+// decisionTokens are branches and boolean operators of C-like, Python, Kotlin and Swift languages
+var decisionTokens = regexp.MustCompile(`\b(if|elif|for|foreach|while|case|catch|except|guard|when)\b|&&|\|\||\?\?|\b(and|or)\b`)
+
+// approximateComplexity counts decision tokens of not comment lines, it is empty for languages without known syntax.
+// Cognitive complexity adds line indentation level as nesting.
+func approximateComplexity(language string, lines []string) Complexity {
+	syntax, ok := commentSyntaxes[language]
+	if !ok {
+		return Complexity{}
+	}
+
+	indentUnit := 0
+	for _, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent > 0 && strings.TrimSpace(line) != "" && (indentUnit == 0 || indent < indentUnit) {
+			indentUnit = indent
+		}
+	}
+
+	c := Complexity{Cyclomatic: 1}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || syntax.lineComment(trimmed) || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+
+		count := uint32(len(decisionTokens.FindAllString(trimmed, -1)))
+		if count == 0 {
+			continue
+		}
+
+		nesting := uint32(0)
+		if indentUnit > 0 {
+			// the first indentation level is function body
+			if level := (len(line) - len(strings.TrimLeft(line, " \t"))) / indentUnit; level > 1 {
+				nesting = uint32(level - 1)
+			}
+		}
+
+		c.Cyclomatic += count
+		c.Cognitive += count + nesting
+	}
+
+	return c
+}
@@ -0,0 +1,69 @@
+package files
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoComplexity(t *testing.T) {
+	content := `package main
+
+func sumOfPrimes(max int) int {
+	total := 0
+OUT:
+	for i := 1; i <= max; i++ {
+		for j := 2; j < i; j++ {
+			if i%j == 0 {
+				continue OUT
+			}
+		}
+		total += i
+	}
+	return total
+}
+
+func get(a, b bool, x int) string {
+	if a && b || a {
+		return "a"
+	} else if x > 1 {
+		return "b"
+	} else {
+		switch x {
+		case 1, 2:
+			return "c"
+		default:
+		}
+	}
+	return ""
+}
+`
+
+	parsed, err := parseGo("main.go", []byte(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, Complexity{
+		Cyclomatic: 10,
+		Cognitive:  14,
+		FunctionsComplexity: []FunctionComplexity{
+			{Name: "sumOfPrimes", Line: 3, Lines: 13, Cyclomatic: 4, Cognitive: 7},
+			{Name: "get", Line: 17, Lines: 14, Cyclomatic: 6, Cognitive: 7},
+		},
+	}, parsed.Complexity)
+}
+
+func TestApproximateComplexity(t *testing.T) {
+	content := `def check(items):
+    # if comment is skipped
+    for item in items:
+        if item and item.valid:
+            return True
+    return False
+`
+
+	assert.Equal(t, Complexity{Cyclomatic: 4, Cognitive: 4},
+		approximateComplexity("Python", strings.Split(content, "\n")))
+	assert.Equal(t, Complexity{}, approximateComplexity("Markdown", strings.Split("if and or", "\n")))
+}
@@ -0,0 +1,122 @@
+package files
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// Declarations is declared functions and types summary, it is filled for Go files
+type Declarations struct {
+	PackageName       string // declared package name, it can differ from folder name
+	Functions         uint32 // functions and methods count
+	ExportedFunctions uint32
+	ExportedTypes     uint32
+	Types             uint32 // all declared types count
+	Interfaces        uint32 // interface types count, it is package abstractness numerator
+	FunctionLines     uint32 // functions lines sum
+	MaxFunctionLines  uint32
+}
+
+// goFile is Go file data parsed from syntax tree
+type goFile struct {
+	Imports []string
+	Declarations
+	Complexity
+}
+
+// parseGo returns exact Go file imports, declarations and functions complexity, file with syntax error is not parsed
+func parseGo(name string, content []byte) (goFile, error) {
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, name, content, parser.SkipObjectResolution)
+	if err != nil {
+		return goFile{}, err
+	}
+
+	imports, decls, err := goDeclarations(fset, f)
+	if err != nil {
+		return goFile{}, err
+	}
+
+	return goFile{
+		Imports:      imports,
+		Declarations: decls,
+		Complexity:   complexityTotals(goFunctionsComplexity(fset, f)),
+	}, nil
+}
+
+func goDeclarations(fset *token.FileSet, f *ast.File) (imports []string, decls Declarations, err error) {
+	decls.PackageName = f.Name.Name
+
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, decls, err
+		}
+
+		imports = append(imports, path)
+	}
+
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			decls.Functions++
+
+			if decl.Name.IsExported() && exportedReceiver(decl) {
+				decls.ExportedFunctions++
+			}
+
+			lines := uint32(fset.Position(decl.End()).Line - fset.Position(decl.Pos()).Line + 1)
+			decls.FunctionLines += lines
+
+			if lines > decls.MaxFunctionLines {
+				decls.MaxFunctionLines = lines
+			}
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range decl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+
+				decls.Types++
+
+				if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+					decls.Interfaces++
+				}
+
+				if typeSpec.Name.IsExported() {
+					decls.ExportedTypes++
+				}
+			}
+		}
+	}
+
+	return imports, decls, nil
+}
+
+// exportedReceiver reports whether function is not a method or its receiver type is exported
+func exportedReceiver(decl *ast.FuncDecl) bool {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return true
+	}
+
+	typ := decl.Recv.List[0].Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		case *ast.Ident:
+			return t.IsExported()
+		default:
+			return false
+		}
+	}
+}
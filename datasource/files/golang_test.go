@@ -0,0 +1,73 @@
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusinikita/devex/slices"
+)
+
+func TestParseGo(t *testing.T) {
+	content := `//go:build linux && cgo
+
+package store // folder name is storage
+
+// #include <stdio.h>
+import "C"
+
+import (
+	"fmt"
+	str "strings"
+	_ "embed"
+	. "math"
+)
+
+var from = "not an import"
+
+type Store struct{}
+
+type option func()
+
+type Getter interface {
+	Get(key string) string
+}
+
+type List[T any] []T
+
+func New() *Store {
+	return &Store{}
+}
+
+func (s *Store) Get(key string) string {
+	return str.ToUpper(fmt.Sprint(key, Pi))
+}
+
+func (l List[T]) Len() int { return len(l) }
+
+func (o option) Apply() {}
+
+func helper() {}
+`
+
+	parsed, err := parseGo("store.go", []byte(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"C", "fmt", "strings", "embed", "math"}, parsed.Imports)
+	assert.Equal(t, Declarations{
+		PackageName:       "store",
+		Functions:         5,
+		ExportedFunctions: 3,
+		ExportedTypes:     3,
+		Types:             4,
+		Interfaces:        1,
+		FunctionLines:     3 + 3 + 1 + 1 + 1,
+		MaxFunctionLines:  3,
+	}, parsed.Declarations)
+	assert.Equal(t, []string{"New", "Store.Get", "List.Len", "option.Apply", "helper"},
+		slices.Map(parsed.FunctionsComplexity, func(f FunctionComplexity) string { return f.Name }))
+
+	_, err = parseGo("broken.go", []byte("package broken\nfunc {"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package files
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ImportParser returns file imports as slash separated paths.
+// Relative imports are joined with file package, so they point to project files.
+type ImportParser func(pkg string, lines []string) []string
+
+// importParsers is language import parsers registry, languages without parser use line prefix heuristics.
+// Go imports are parsed from syntax tree.
+var importParsers = map[string]ImportParser{
+	"Python":     pythonImports,
+	"JavaScript": javascriptImports,
+	"TypeScript": javascriptImports,
+	"Vue":        javascriptImports,
+	"Svelte":     javascriptImports,
+	"Java":       jvmImports,
+	"Kotlin":     jvmImports,
+	"Scala":      jvmImports,
+	"Swift":      swiftImports,
+}
+
+// RegisterImportParser adds or replaces language import parser
+func RegisterImportParser(language string, parser ImportParser) {
+	importParsers[language] = parser
+}
+
+func parseImports(language, pkg string, lines []string) []string {
+	parser, ok := importParsers[language]
+	if !ok {
+		return extractImports(lines)
+	}
+
+	return parser(pkg, lines)
+}
+
+var (
+	pythonImport     = regexp.MustCompile(`^import\s+(.+)$`)
+	pythonFromImport = regexp.MustCompile(`^from\s+(\.*)([\w.]*)\s+import\s+\(?([^)#]*)`)
+)
+
+// pythonImports returns modules of "import a.b" and "from a.b import c" statements.
+// Imported names can be modules or module members, so both "a/b" and "a/b/c" are returned.
+// Relative imports are resolved by file package.
+func pythonImports(pkg string, lines []string) (imports []string) {
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if m := pythonImport.FindStringSubmatch(line); m != nil {
+			for _, module := range splitNames(m[1]) {
+				imports = append(imports, strings.ReplaceAll(module, ".", "/"))
+			}
+
+			continue
+		}
+
+		m := pythonFromImport.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		names := m[3]
+		// names list in brackets can be multiline
+		if strings.Contains(line, "(") && !strings.Contains(line, ")") {
+			for i+1 < len(lines) {
+				i++
+				name, _, closed := strings.Cut(lines[i], ")")
+				names += "," + name
+
+				if closed {
+					break
+				}
+			}
+		}
+
+		module := strings.ReplaceAll(m[2], ".", "/")
+		if dots := len(m[1]); dots > 0 {
+			base := pkg
+			for j := 1; j < dots; j++ {
+				base = path.Dir(base)
+			}
+
+			module = path.Join(base, module)
+		}
+
+		if module != "" && module != "." {
+			imports = append(imports, module)
+		}
+
+		for _, name := range splitNames(names) {
+			if name != "*" {
+				imports = append(imports, path.Join(module, name))
+			}
+		}
+	}
+
+	return imports
+}
+
+// splitNames splits "a as b, c" import names list and drops aliases and comments
+func splitNames(names string) (result []string) {
+	names, _, _ = strings.Cut(names, "#")
+
+	for _, name := range strings.Split(names, ",") {
+		fields := strings.Fields(name)
+		if len(fields) == 0 {
+			continue
+		}
+
+		result = append(result, fields[0])
+	}
+
+	return result
+}
+
+var javascriptImport = regexp.MustCompile(`(?:\bimport\b[^'"]*?\bfrom\s*|\bimport\s*\(?\s*|\brequire\s*\(\s*|\bexport\b[^'"]*?\bfrom\s*)['"]([^'"]+)['"]`)
+
+// javascriptSourceExtensions are trimmed from imports, other dots are module name parts, e.g. "./user.component"
+var javascriptSourceExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true, ".vue": true,
+}
+
+// javascriptImports returns ES modules imports, dynamic imports and CommonJS requires.
+// Relative paths are joined with file package, source file extensions are trimmed.
+func javascriptImports(pkg string, lines []string) (imports []string) {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+
+		for _, m := range javascriptImport.FindAllStringSubmatch(trimmed, -1) {
+			module := m[1]
+			if strings.HasPrefix(module, "./") || strings.HasPrefix(module, "../") {
+				module = path.Join(pkg, module)
+				if ext := path.Ext(module); javascriptSourceExtensions[ext] {
+					module = strings.TrimSuffix(module, ext)
+				}
+			}
+
+			imports = append(imports, module)
+		}
+	}
+
+	return imports
+}
+
+var jvmImport = regexp.MustCompile(`^import\s+(static\s+)?([\w.]+(?:\.\*)?)(?:\s+as\s+\w+)?\s*;?$`)
+
+// jvmImports returns Java, Kotlin and Scala imported classes as paths, e.g. "import a.b.C" is "a/b/C".
+// Wildcard import is package path, static import is class path.
+func jvmImports(_ string, lines []string) (imports []string) {
+	for _, line := range lines {
+		m := jvmImport.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		name := strings.TrimSuffix(m[2], ".*")
+		// static import is class member
+		if i := strings.LastIndex(name, "."); m[1] != "" && !strings.HasSuffix(m[2], ".*") && i > 0 {
+			name = name[:i]
+		}
+
+		imports = append(imports, strings.ReplaceAll(name, ".", "/"))
+	}
+
+	return imports
+}
+
+var swiftImport = regexp.MustCompile(`^(?:@\w+\s+)*import\s+(?:(?:typealias|struct|class|enum|protocol|let|var|func)\s+)?([\w.]+)`)
+
+// swiftImports returns imported modules, module is project folder name, e.g. "import Core.Network" is "Core"
+func swiftImports(_ string, lines []string) (imports []string) {
+	for _, line := range lines {
+		m := swiftImport.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		module, _, _ := strings.Cut(m[1], ".")
+
+		imports = append(imports, module)
+	}
+
+	return imports
+}
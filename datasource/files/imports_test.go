@@ -0,0 +1,94 @@
+package files
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPythonImports(t *testing.T) {
+	content := `
+import os, sys as system
+import black.nodes
+from black.mode import Mode, TargetVersion
+from . import trans
+from ..blib2to3 import pytree
+from .linegen import (
+    LineGenerator,
+    transform_line,
+)
+from typing import *  # comment
+`
+
+	expect := []string{
+		"os", "sys",
+		"black/nodes",
+		"black/mode", "black/mode/Mode", "black/mode/TargetVersion",
+		"src/black", "src/black/trans",
+		"src/blib2to3", "src/blib2to3/pytree",
+		"src/black/linegen", "src/black/linegen/LineGenerator", "src/black/linegen/transform_line",
+		"typing",
+	}
+
+	assert.Equal(t, expect, pythonImports("src/black", strings.Split(content, "\n")))
+}
+
+func TestJavascriptImports(t *testing.T) {
+	content := `
+import React, { useState } from 'react';
+import './styles.css'
+import type { User } from "../models/user.ts"
+export { Button } from './button'
+import { UserComponent } from './user.component'
+import App from './App.vue'
+// import commented from 'commented'
+const lodash = require('lodash/fp')
+const page = await import("./pages/home")
+`
+
+	expect := []string{
+		"react",
+		"src/app/styles.css",
+		"src/models/user",
+		"src/app/button",
+		"src/app/user.component",
+		"src/app/App",
+		"lodash/fp",
+		"src/app/pages/home",
+	}
+
+	assert.Equal(t, expect, javascriptImports("src/app", strings.Split(content, "\n")))
+}
+
+func TestJVMImports(t *testing.T) {
+	content := `
+package com.example.app;
+
+import java.util.List;
+import com.example.core.*;
+import static org.junit.Assert.assertEquals;
+import static com.example.Utils.*;
+import com.example.db.Store as DBStore
+`
+
+	expect := []string{
+		"java/util/List",
+		"com/example/core",
+		"org/junit/Assert",
+		"com/example/Utils",
+		"com/example/db/Store",
+	}
+
+	assert.Equal(t, expect, jvmImports("", strings.Split(content, "\n")))
+}
+
+func TestSwiftImports(t *testing.T) {
+	content := `
+import UIKit
+@testable import Core
+import struct Networking.Request
+`
+
+	assert.Equal(t, []string{"UIKit", "Core", "Networking"}, swiftImports("", strings.Split(content, "\n")))
+}
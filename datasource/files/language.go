@@ -0,0 +1,236 @@
+package files
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// languagesByExtension maps lower case file extension to language, ambiguous ones are resolved by content
+var languagesByExtension = map[string]string{
+	".go":     "Go",
+	".py":     "Python",
+	".pyi":    "Python",
+	".js":     "JavaScript",
+	".mjs":    "JavaScript",
+	".cjs":    "JavaScript",
+	".jsx":    "JavaScript",
+	".ts":     "TypeScript",
+	".tsx":    "TypeScript",
+	".java":   "Java",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".swift":  "Swift",
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".cxx":    "C++",
+	".hh":     "C++",
+	".hpp":    "C++",
+	".m":      "Objective-C",
+	".mm":     "Objective-C",
+	".cs":     "C#",
+	".rb":     "Ruby",
+	".php":    "PHP",
+	".rs":     "Rust",
+	".scala":  "Scala",
+	".dart":   "Dart",
+	".lua":    "Lua",
+	".pl":     "Perl",
+	".pm":     "Perl",
+	".r":      "R",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".erl":    "Erlang",
+	".hs":     "Haskell",
+	".clj":    "Clojure",
+	".groovy": "Groovy",
+	".gradle": "Groovy",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".zsh":    "Shell",
+	".sql":    "SQL",
+	".proto":  "Protocol Buffers",
+	".html":   "HTML",
+	".htm":    "HTML",
+	".gohtml": "HTML",
+	".css":    "CSS",
+	".scss":   "SCSS",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".json":   "JSON",
+	".yaml":   "YAML",
+	".yml":    "YAML",
+	".toml":   "TOML",
+	".xml":    "XML",
+	".md":     "Markdown",
+	".rst":    "reStructuredText",
+	".tf":     "HCL",
+}
+
+var languagesByName = map[string]string{
+	"Makefile":       "Makefile",
+	"GNUmakefile":    "Makefile",
+	"Dockerfile":     "Dockerfile",
+	"Gemfile":        "Ruby",
+	"Rakefile":       "Ruby",
+	"Jenkinsfile":    "Groovy",
+	"CMakeLists.txt": "CMake",
+	"go.mod":         "Go Module",
+	"go.sum":         "Go Checksums",
+}
+
+var languagesByInterpreter = map[string]string{
+	"python":  "Python",
+	"python2": "Python",
+	"python3": "Python",
+	"node":    "JavaScript",
+	"deno":    "TypeScript",
+	"ruby":    "Ruby",
+	"perl":    "Perl",
+	"php":     "PHP",
+	"sh":      "Shell",
+	"bash":    "Shell",
+	"zsh":     "Shell",
+	"lua":     "Lua",
+}
+
+// heuristics resolve ambiguous extensions by content like github linguist does
+var heuristics = map[string][]struct {
+	language string
+	pattern  *regexp.Regexp
+}{
+	".h": {
+		{"Objective-C", regexp.MustCompile(`(?m)^\s*(@interface|@protocol|@end|#import)\b`)},
+		{"C++", regexp.MustCompile(`(?m)^\s*(class\s+\w+\s*[:{]|namespace\s+\w+|template\s*<|#include\s*<(iostream|string|vector|memory)>)`)},
+	},
+	".m": {
+		{"Objective-C", regexp.MustCompile(`(?m)^\s*(@interface|@implementation|@protocol|#import|#include)\b`)},
+		{"MATLAB", regexp.MustCompile(`(?m)^\s*(function\s.*=|end\s*$|%)`)},
+	},
+	".pl": {
+		{"Prolog", regexp.MustCompile(`(?m)^\s*:-|^[a-z]\w*(\(.*\))?\s*:-`)},
+	},
+	".r": {
+		{"Rebol", regexp.MustCompile(`(?i)\bREBOL\s*\[`)},
+	},
+}
+
+// DetectLanguage returns file language by name, shebang or extension. Empty string is unknown language.
+func DetectLanguage(name string, content []byte) string {
+	if language, ok := languagesByName[name]; ok {
+		return language
+	}
+
+	if language := shebangLanguage(content); language != "" {
+		return language
+	}
+
+	ext := strings.ToLower(path.Ext(name))
+
+	for _, h := range heuristics[ext] {
+		if h.pattern.Match(content) {
+			return h.language
+		}
+	}
+
+	return languagesByExtension[ext]
+}
+
+// shebangLanguage returns script interpreter language, e.g. "#!/usr/bin/env python3" is Python
+func shebangLanguage(content []byte) string {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return ""
+	}
+
+	line, _, _ := bytes.Cut(content[2:], []byte("\n"))
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		// env options like -S are skipped
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				interpreter = f
+				break
+			}
+		}
+	}
+
+	return languagesByInterpreter[interpreter]
+}
+
+// languageRule is .gitattributes linguist-language override, e.g. "*.inc linguist-language=PHP"
+type languageRule struct {
+	pattern  gitignore.Pattern
+	language string
+}
+
+// readLanguageRules reads linguist-language attributes, it returns no rules if file is absent
+func readLanguageRules(path string, domain []string) (rules []languageRule, err error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		for _, attr := range fields[1:] {
+			if language, ok := strings.CutPrefix(attr, "linguist-language="); ok {
+				rules = append(rules, languageRule{
+					pattern:  gitignore.ParsePattern(fields[0], domain),
+					language: knownLanguage(language),
+				})
+			}
+		}
+	}
+
+	return rules, scanner.Err()
+}
+
+// knownLanguage returns detector language name for attribute value, attributes can't contain spaces,
+// so "protocol-buffers" is "Protocol Buffers". Unknown languages are kept as is.
+func knownLanguage(attr string) string {
+	for _, languages := range []map[string]string{languagesByExtension, languagesByName, languagesByInterpreter} {
+		for _, language := range languages {
+			if strings.EqualFold(language, attr) || strings.EqualFold(strings.ReplaceAll(language, " ", "-"), attr) {
+				return language
+			}
+		}
+	}
+
+	return attr
+}
+
+// overriddenLanguage returns language of the last matched rule
+func overriddenLanguage(rules []languageRule, path []string) (language string) {
+	for _, r := range rules {
+		if r.pattern.Match(path, false) == gitignore.Exclude {
+			language = r.language
+		}
+	}
+
+	return language
+}
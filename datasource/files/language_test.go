@@ -0,0 +1,50 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"main.go", "package main", "Go"},
+		{"App.KT", "class App", "Kotlin"},
+		{"Makefile", "build:", "Makefile"},
+		{"deploy", "#!/usr/bin/env -S python3 -u\nprint()", "Python"},
+		{"run", "#!/bin/bash\necho", "Shell"},
+		{"view.h", "#import <UIKit/UIKit.h>\n@interface View : UIView", "Objective-C"},
+		{"vector.h", "namespace math {\nclass Vector {};\n}", "C++"},
+		{"util.h", "int sum(int a, int b);", "C"},
+		{"plot.m", "function y = plot(x)\n  y = x;\nend", "MATLAB"},
+		{"notes", "some text", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, DetectLanguage(test.name, []byte(test.content)))
+		})
+	}
+}
+
+func TestReadLanguageRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitattributes")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\n*.inc linguist-language=php\n*.pbtxt linguist-language=protocol-buffers\n*.pb.go -diff\ngen/*.h linguist-language=Objective-C++\n"), 0o644))
+
+	rules, err := readLanguageRules(path, []string{"src"})
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	assert.Equal(t, "PHP", overriddenLanguage(rules, []string{"src", "lib", "db.inc"}))
+	assert.Equal(t, "Protocol Buffers", overriddenLanguage(rules, []string{"src", "config.pbtxt"}))
+	assert.Equal(t, "Objective-C++", overriddenLanguage(rules, []string{"src", "gen", "view.h"}))
+	assert.Equal(t, "", overriddenLanguage(rules, []string{"lib", "db.inc"}))
+	assert.Equal(t, "", overriddenLanguage(rules, []string{"src", "api.pb.go"}))
+}
@@ -0,0 +1,232 @@
+package files
+
+import (
+	"strings"
+)
+
+// commentSyntax is language comment delimiters
+type commentSyntax struct {
+	line   []string
+	blocks [][2]string
+	// nested block comments are allowed in Kotlin and Swift
+	nested bool
+	// docstrings are block comments only at the line beginning, otherwise they are strings
+	docstrings bool
+	// backquoted strings can be multiline, e.g. Go raw strings and JavaScript template literals
+	rawStrings bool
+}
+
+var (
+	cStyleComments    = commentSyntax{line: []string{"//"}, blocks: [][2]string{{"/*", "*/"}}}
+	rawStringComments = commentSyntax{line: []string{"//"}, blocks: [][2]string{{"/*", "*/"}}, rawStrings: true}
+	nestedComments    = commentSyntax{line: []string{"//"}, blocks: [][2]string{{"/*", "*/"}}, nested: true}
+	// python docstrings are counted as comments
+	pythonComments = commentSyntax{line: []string{"#"}, blocks: [][2]string{{`"""`, `"""`}, {`'''`, `'''`}}, docstrings: true}
+)
+
+var commentSyntaxes = map[string]commentSyntax{
+	"Go":         rawStringComments,
+	"Java":       cStyleComments,
+	"JavaScript": rawStringComments,
+	"TypeScript": rawStringComments,
+	"C":          cStyleComments,
+	"C++":        cStyleComments,
+	"C#":         cStyleComments,
+	"Kotlin":     nestedComments,
+	"Swift":      nestedComments,
+	"Python":     pythonComments,
+}
+
+// LineCounts is file code, comment and blank lines count
+type LineCounts struct {
+	Code    uint32
+	Comment uint32
+	Blank   uint32
+}
+
+// countLines classifies lines by language comment syntax.
+// Line with code and comment is code line. Lines of languages without known syntax are code or blank.
+func countLines(language string, lines []string) (counts LineCounts) {
+	syntax := commentSyntaxes[language]
+
+	// trailing new line is not a blank line
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	// block is current block comment, depth is nested comments depth
+	var block [2]string
+	depth := 0
+
+	// multiline string started after code, its lines are code
+	var multilineString string
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			counts.Blank++
+			continue
+		}
+
+		hasCode := false
+
+		// code after multiline string end is handled as usual line
+		if multilineString != "" {
+			end := strings.Index(line, multilineString)
+			if end < 0 {
+				counts.Code++
+				continue
+			}
+
+			line = line[end+len(multilineString):]
+			multilineString = ""
+			hasCode = true
+		}
+
+		for line != "" {
+			if depth > 0 {
+				end := strings.Index(line, block[1])
+				start := -1
+				if syntax.nested {
+					start = strings.Index(line, block[0])
+				}
+
+				switch {
+				case start >= 0 && (end < 0 || start < end):
+					depth++
+					line = line[start+len(block[0]):]
+				case end >= 0:
+					depth--
+					line = line[end+len(block[1]):]
+				default:
+					line = ""
+				}
+
+				continue
+			}
+
+			line = strings.TrimSpace(line)
+			if line == "" || syntax.lineComment(line) {
+				break
+			}
+
+			if b, ok := syntax.blockStart(line); ok {
+				block, depth = b, 1
+				line = line[len(b[0]):]
+
+				continue
+			}
+
+			hasCode = true
+
+			if syntax.docstrings {
+				multilineString = syntax.unclosedString(line)
+			}
+
+			// comment after code is skipped, but block comment started after code continues on next lines
+			next, unclosed := syntax.nextComment(line)
+			if unclosed != "" {
+				multilineString = unclosed
+			}
+
+			if next < 0 {
+				break
+			}
+
+			line = line[next:]
+		}
+
+		if hasCode {
+			counts.Code++
+		} else {
+			counts.Comment++
+		}
+	}
+
+	return counts
+}
+
+func (s commentSyntax) lineComment(line string) bool {
+	for _, prefix := range s.line {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s commentSyntax) blockStart(line string) ([2]string, bool) {
+	for _, b := range s.blocks {
+		if strings.HasPrefix(line, b[0]) {
+			return b, true
+		}
+	}
+
+	return [2]string{}, false
+}
+
+// unclosedString returns docstring delimiter if line has unclosed multiline string
+func (s commentSyntax) unclosedString(line string) string {
+	for _, b := range s.blocks {
+		if strings.Count(line, b[0])%2 == 1 {
+			return b[0]
+		}
+	}
+
+	return ""
+}
+
+// nextComment returns index of the first comment start, it is -1 if line has no comments.
+// Quoted and backquoted string literals are skipped, so "vendor/*" is not a comment start.
+// unclosed is quote of multiline string that continues on next lines.
+func (s commentSyntax) nextComment(line string) (next int, unclosed string) {
+	for i := 0; i < len(line); i++ {
+		switch quote := line[i]; quote {
+		case '"', '\'', '`':
+			end := stringEnd(line, i)
+			if end < 0 {
+				if quote == '`' && s.rawStrings {
+					return -1, "`"
+				}
+
+				return -1, ""
+			}
+
+			i = end
+
+			continue
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		rest := line[i:]
+		if s.lineComment(rest) {
+			return i, ""
+		}
+
+		// python docstrings after code are strings
+		if _, ok := s.blockStart(rest); ok && !s.docstrings {
+			return i, ""
+		}
+	}
+
+	return -1, ""
+}
+
+// stringEnd returns index of string literal closing quote, it is -1 if string is not closed on the line
+func stringEnd(line string, start int) int {
+	quote := line[start]
+	for i := start + 1; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && quote != '`':
+			i++
+		case line[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,109 @@
+package files
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		content  string
+		expected LineCounts
+	}{
+		{
+			name:     "go",
+			language: "Go",
+			content: `// Package main is example
+package main
+
+/*
+block comment
+*/
+func main() { // trailing comment
+	x := 1 /* inline */ + 2
+	/* comment */ println(x)
+	/* one line block */
+}
+`,
+			expected: LineCounts{Code: 5, Comment: 5, Blank: 1},
+		},
+		{
+			name:     "comment delimiters in strings",
+			language: "Go",
+			content: "pattern := \"vendor/*\"\n" +
+				"url := `http://example.com` // comment\n" +
+				"quote := \"\\\"//\" + '#'\n" +
+				"x := 1\n" +
+				"y := 2\n",
+			expected: LineCounts{Code: 5},
+		},
+		{
+			name:     "multiline raw strings",
+			language: "Go",
+			content: "query := `select *\n" +
+				"// not a comment\n" +
+				"from t /* not a block\n" +
+				"` // comment\n" +
+				"x := 1\n" +
+				"// comment\n",
+			expected: LineCounts{Code: 5, Comment: 1},
+		},
+		{
+			name:     "template literals",
+			language: "TypeScript",
+			content: "const url = `http://${host}\n" +
+				"/*`; const y = 2\n" +
+				"const z = 3\n",
+			expected: LineCounts{Code: 3},
+		},
+		{
+			name:     "python comment in string",
+			language: "Python",
+			content: `glob = "*.py  # all"
+x = 1
+`,
+			expected: LineCounts{Code: 2},
+		},
+		{
+			name:     "kotlin nested comments",
+			language: "Kotlin",
+			content: `/* outer
+/* inner */
+still comment */
+val x = 1`,
+			expected: LineCounts{Code: 1, Comment: 3},
+		},
+		{
+			name:     "python docstrings",
+			language: "Python",
+			content: `"""Module docstring.
+
+Details.
+"""
+import os  # comment
+
+# comment
+x = """not a docstring
+still string
+"""
+`,
+			expected: LineCounts{Code: 4, Comment: 4, Blank: 2},
+		},
+		{
+			name:     "unknown language",
+			language: "",
+			content:  "# title\n\ntext\n",
+			expected: LineCounts{Code: 2, Blank: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, countLines(test.language, strings.Split(test.content, "\n")))
+		})
+	}
+}
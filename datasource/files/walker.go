@@ -1,18 +1,26 @@
 package files
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
 
 type File struct {
-	Package string
-	Name    string
-	Lines   uint32
+	Package  string
+	Name     string
+	Language string
+	Lines    uint32
+	LineCounts
+	Declarations
+	Complexity
 	Symbols uint32
 	Tags    map[string]uint32
 	Imports []string
@@ -20,53 +28,172 @@ type File struct {
 
 var Tags = []string{"todo", "fix", "note", "nolint", "billing", "money", "order", "pylint: disable"}
 
-func Extract(_ context.Context, rootPath string, c chan<- File) error {
-	defer close(c)
+// Rules are project include and exclude patterns in .gitignore syntax, e.g. "vendor/", "*.pb.go".
+// Empty include matches all files.
+type Rules struct {
+	Include []string
+	Exclude []string
+}
+
+func Extract(ctx context.Context, rootPath string, c chan<- File) error {
+	return ExtractWithRules(Rules{})(ctx, rootPath, c)
+}
+
+// ExtractWithRules returns files extractor skipping files ignored by .gitignore files and exclude rules
+func ExtractWithRules(rules Rules) func(ctx context.Context, rootPath string, c chan<- File) error {
+	include := gitignore.NewMatcher(parsePatterns(rules.Include, nil))
+
+	return func(_ context.Context, rootPath string, c chan<- File) error {
+		defer close(c)
 
-	return filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		// git info exclude is checked before .gitignore files, so .gitignore can override it
+		ignored, err := readPatterns(filepath.Join(rootPath, ".git", "info", "exclude"), nil)
 		if err != nil {
 			return err
 		}
 
-		if d.IsDir() {
-			return nil
-		}
+		excluded := parsePatterns(rules.Exclude, nil)
 
-		fPackage := strings.TrimPrefix(filepath.ToSlash(filepath.Dir(path)), filepath.ToSlash(rootPath))
-		fPackage = strings.TrimPrefix(fPackage, "/")
+		var languages []languageRule
 
-		fName := filepath.Base(path)
+		return filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
 
-		if strings.HasPrefix(fPackage, ".") || strings.HasPrefix(fName, ".") {
-			return nil
-		}
+			relPath, err := filepath.Rel(rootPath, path)
+			if err != nil {
+				return err
+			}
 
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
+			var parts []string
+			if relPath != "." {
+				parts = strings.Split(filepath.ToSlash(relPath), "/")
+			}
+
+			if len(parts) > 0 {
+				if strings.HasPrefix(d.Name(), ".") {
+					return skip(d)
+				}
+
+				// project exclude rules are the last, they override .gitignore negations
+				if gitignore.NewMatcher(append(ignored, excluded...)).Match(parts, d.IsDir()) {
+					return skip(d)
+				}
+			}
+
+			if d.IsDir() {
+				// nested .gitignore patterns have directory domain, they don't affect other directories
+				patterns, err := readPatterns(filepath.Join(path, ".gitignore"), parts)
+				if err != nil {
+					return err
+				}
+
+				ignored = append(ignored, patterns...)
+
+				dirLanguages, err := readLanguageRules(filepath.Join(path, ".gitattributes"), parts)
+				if err != nil {
+					return err
+				}
+
+				languages = append(languages, dirLanguages...)
+
+				return nil
+			}
+
+			if len(rules.Include) > 0 && !include.Match(parts, false) {
+				return nil
+			}
+
+			fPackage := strings.Join(parts[:len(parts)-1], "/")
+			fName := d.Name()
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			// skip not code files
+			if !strings.HasPrefix(http.DetectContentType(data), "text/") {
+				return nil
+			}
+
+			language := overriddenLanguage(languages, parts)
+			if language == "" {
+				language = DetectLanguage(fName, data)
+			}
+
+			content := string(data)
+			lines := strings.Split(content, "\n")
+
+			f := File{
+				Package:    fPackage,
+				Name:       fName,
+				Language:   language,
+				Lines:      uint32(len(lines)),
+				LineCounts: countLines(language, lines),
+				Symbols:    uint32(len(content)),
+				Imports:    parseImports(language, fPackage, lines),
+				Tags:       extractTags(content),
+				Complexity: approximateComplexity(language, lines),
+			}
+
+			// line heuristics are used for Go files with syntax errors
+			if language == "Go" {
+				if parsed, err := parseGo(path, data); err == nil {
+					f.Imports, f.Declarations, f.Complexity = parsed.Imports, parsed.Declarations, parsed.Complexity
+				}
+			}
+
+			c <- f
 
-		// skip not code files
-		if !strings.HasPrefix(http.DetectContentType(data), "text/") {
 			return nil
-		}
+		})
+	}
+}
+
+func skip(d fs.DirEntry) error {
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
 
-		content := string(data)
-		lines := strings.Split(content, "\n")
+	return nil
+}
 
-		f := File{
-			Package: fPackage,
-			Name:    fName,
-			Lines:   uint32(len(lines)),
-			Symbols: uint32(len(content)),
-			Imports: extractImports(lines),
-			Tags:    extractTags(content),
+func parsePatterns(lines []string, domain []string) (patterns []gitignore.Pattern) {
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
 		}
 
-		c <- f
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+
+	return patterns
+}
+
+// readPatterns reads ignore file patterns, it returns no patterns if file is absent
+func readPatterns(path string, domain []string) ([]gitignore.Pattern, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var lines []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
 
-		return nil
-	})
+	return parsePatterns(lines, domain), scanner.Err()
 }
 
 func extractTags(content string) (tags map[string]uint32) {
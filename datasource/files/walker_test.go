@@ -2,6 +2,9 @@ package files
 
 import (
 	"context"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -146,3 +149,52 @@ def maybe_install_uvloop() -> None:
 		assert.Equal(t, expect, extractImports(strings.Split(content, "\n")))
 	})
 }
+
+func TestExtractWithRules(t *testing.T) {
+	root := t.TempDir()
+
+	write := func(path, content string) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	write(".gitignore", "vendor/\n*.log\n!keep.log\n")
+	write(".git/info/exclude", "local.go\n")
+	write("main.go", "package main")
+	write("local.go", "package main")
+	write("debug.log", "log")
+	write("keep.log", "log")
+	write("vendor/lib/lib.go", "package lib")
+	write("api/.gitignore", "*.pb.go\n")
+	write("api/api.go", "package api")
+	write("api/api.pb.go", "package api")
+	write("other/api.pb.go", "package other")
+	write("docs/readme.md", "docs")
+	write(".github/ci.yml", "ci")
+
+	extract := func(rules Rules) (paths []string) {
+		c := make(chan File)
+
+		wg, ctx := errgroup.WithContext(context.TODO())
+		wg.Go(func() error {
+			return ExtractWithRules(rules)(ctx, root, c)
+		})
+
+		for f := range c {
+			paths = append(paths, path.Join(f.Package, f.Name))
+		}
+
+		require.NoError(t, wg.Wait())
+
+		return paths
+	}
+
+	assert.ElementsMatch(t, []string{
+		"main.go", "keep.log", "api/api.go", "other/api.pb.go", "docs/readme.md",
+	}, extract(Rules{}))
+
+	assert.ElementsMatch(t, []string{
+		"main.go", "api/api.go",
+	}, extract(Rules{Include: []string{"*.go"}, Exclude: []string{"docs/", "other/"}}))
+}
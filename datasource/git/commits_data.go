@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 )
 
 type Commit struct {
@@ -25,24 +26,49 @@ type FileCommit struct {
 	RowsRemoved uint32
 }
 
+// ExtractCommits returns commits reachable from HEAD, the same commits are walked by project update
 func ExtractCommits(ctx context.Context, projectPath string, c chan<- Commit) error {
-	defer close(c)
+	return ExtractCommitsExcept(nil)(ctx, projectPath, c)
+}
 
-	repository, err := git.PlainOpen(projectPath)
-	if err != nil {
-		return err
-	}
+// ExtractCommitsExcept returns extractor of commits reachable from HEAD without known commits.
+// All commits are walked, because rebased, cherry-picked and merged commits are not ordered by time after known ones.
+func ExtractCommitsExcept(known map[string]bool) func(ctx context.Context, projectPath string, c chan<- Commit) error {
+	return func(ctx context.Context, projectPath string, c chan<- Commit) error {
+		defer close(c)
 
-	commitObjects, err := repository.CommitObjects()
-	if err != nil {
-		return err
+		repository, err := git.PlainOpen(projectPath)
+		if err != nil {
+			return err
+		}
+
+		commitObjects, err := repository.Log(&git.LogOptions{Order: git.LogOrderCommitterTime})
+		if err != nil {
+			return err
+		}
+
+		mailmap, err := ReadMailmap(projectPath)
+		if err != nil {
+			return err
+		}
+
+		send := sendCommit(ctx, mailmap, c)
+
+		return commitObjects.ForEach(func(commit *object.Commit) error {
+			if known[commit.Hash.String()] {
+				return nil
+			}
+
+			return send(commit)
+		})
 	}
+}
 
-	return commitObjects.ForEach(func(commit *object.Commit) error {
+func sendCommit(ctx context.Context, mailmap Mailmap, c chan<- Commit) func(commit *object.Commit) error {
+	return func(commit *object.Commit) error {
 		select {
 		case <-ctx.Done():
-			commitObjects.Close()
-			return nil
+			return storer.ErrStop
 		default:
 		}
 
@@ -63,12 +89,27 @@ func ExtractCommits(ctx context.Context, projectPath string, c chan<- Commit) er
 
 		c <- Commit{
 			Hash:    commit.Hash.String(),
-			Author:  commit.Author.Email,
+			Author:  mailmap.Author(commit.Author.Name, commit.Author.Email),
 			Message: commit.Message,
 			Time:    commit.Author.When,
 			Files:   files,
 		}
 
 		return nil
-	})
+	}
+}
+
+// HeadHash returns project repository HEAD commit hash
+func HeadHash(projectPath string) (string, error) {
+	repository, err := git.PlainOpen(projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
 }
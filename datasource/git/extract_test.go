@@ -6,10 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,7 +49,7 @@ func createTestRepository(t *testing.T, commits, authors, files int) string {
 			Author: &object.Signature{
 				Name:  fmt.Sprintf("%d Name", i%authors),
 				Email: fmt.Sprintf("%d@test.com", i%authors),
-				When:  time.Now(),
+				When:  time.Now().Add(time.Duration(i-commits) * time.Minute),
 			},
 		})
 		require.NoError(t, err)
@@ -84,6 +86,111 @@ func TestExtract(t *testing.T) {
 	assert.Equal(t, 4, len(files))
 }
 
+func TestExtractExcept(t *testing.T) {
+	path := createTestRepository(t, 10, 3, 4)
+
+	all := make(chan git2.Commit, 20)
+	require.NoError(t, git2.ExtractCommits(context.TODO(), path, all))
+
+	// known commits are not the newest ones, e.g. commit 8 author time is older after rebase
+	known := map[string]bool{}
+	for commit := range all {
+		if commit.Message != "9 commit" && commit.Message != "7 commit" && commit.Message != "2 commit" {
+			known[commit.Hash] = true
+		}
+	}
+
+	c := make(chan git2.Commit, 20)
+	require.NoError(t, git2.ExtractCommitsExcept(known)(context.TODO(), path, c))
+
+	var messages []string
+	for commit := range c {
+		messages = append(messages, commit.Message)
+	}
+
+	assert.Equal(t, []string{"9 commit", "7 commit", "2 commit"}, messages)
+}
+
+func TestExtractHeadCommits(t *testing.T) {
+	path := createTestRepository(t, 3, 1, 1)
+
+	repository, err := git.PlainOpen(path)
+	require.NoError(t, err)
+
+	head, err := repository.Head()
+	require.NoError(t, err)
+
+	worktree, err := repository.Worktree()
+	require.NoError(t, err)
+
+	// commit of other branch is not reachable from HEAD, update doesn't see it too
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("other"), Create: true}))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "other.txt"), []byte("other"), os.ModePerm))
+	_, err = worktree.Add("other.txt")
+	require.NoError(t, err)
+	_, err = worktree.Commit("other commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Other", Email: "other@test.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: head.Name()}))
+
+	c := make(chan git2.Commit, 5)
+	require.NoError(t, git2.ExtractCommits(context.TODO(), path, c))
+
+	var messages []string
+	for commit := range c {
+		messages = append(messages, commit.Message)
+	}
+
+	assert.Equal(t, []string{"2 commit", "1 commit", "0 commit"}, messages)
+}
+
+func TestHeadHash(t *testing.T) {
+	path := createTestRepository(t, 3, 1, 1)
+
+	c := make(chan git2.Commit, 5)
+	require.NoError(t, git2.ExtractCommitsExcept(nil)(context.TODO(), path, c))
+
+	head := <-c
+
+	hash, err := git2.HeadHash(path)
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash, hash)
+}
+
+func TestExtractMailmap(t *testing.T) {
+	path := createTestRepository(t, 6, 3, 2)
+	require.NoError(t, os.WriteFile(filepath.Join(path, ".mailmap"), []byte("Zero <0@test.com> <1@TEST.com>\n"), os.ModePerm))
+
+	c := make(chan git2.Commit, 10)
+	require.NoError(t, git2.ExtractCommits(context.TODO(), path, c))
+
+	authors := map[string]int{}
+	for commit := range c {
+		authors[commit.Author]++
+	}
+
+	assert.Equal(t, map[string]int{"0@test.com": 4, "2@test.com": 2}, authors)
+}
+
+func TestParseMailmap(t *testing.T) {
+	mailmap, err := git2.ParseMailmap(strings.NewReader(`
+# comment
+Only Name <name@test.com>
+<proper@test.com> <Commit@Test.com>
+Proper <proper@test.com> <other@test.com> # comment
+Proper <proper@test.com> Old Name <shared@test.com>
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "name@test.com", mailmap.Author("", "name@test.com"))
+	assert.Equal(t, "proper@test.com", mailmap.Author("", "commit@test.com"))
+	assert.Equal(t, "proper@test.com", mailmap.Author("Any", "other@test.com"))
+	assert.Equal(t, "proper@test.com", mailmap.Author("Old Name", "shared@test.com"))
+	assert.Equal(t, "proper@test.com", mailmap.Author("old name", "Shared@Test.com"))
+	assert.Equal(t, "shared@test.com", mailmap.Author("New Name", "shared@test.com"))
+}
+
 func TestRealExtract(t *testing.T) {
 	t.Skip("for local test only")
 
@@ -0,0 +1,99 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mailmap maps commit author emails to canonical ones, see git-check-mailmap docs.
+// Only emails are mapped, author names are not stored.
+type Mailmap struct {
+	byEmail     map[string]string // commit email -> proper email
+	byNameEmail map[string]string // commit name + email -> proper email
+}
+
+// Author returns canonical author email, commit name and email are matched case-insensitively like git does
+func (m Mailmap) Author(name, email string) string {
+	key := strings.ToLower(email)
+
+	if proper, ok := m.byNameEmail[nameEmailKey(name, key)]; ok {
+		return proper
+	}
+
+	if proper, ok := m.byEmail[key]; ok {
+		return proper
+	}
+
+	return email
+}
+
+// ReadMailmap reads .mailmap file from repository root, it returns empty mailmap if file is absent
+func ReadMailmap(projectPath string) (Mailmap, error) {
+	f, err := os.Open(filepath.Join(projectPath, ".mailmap"))
+	if errors.Is(err, os.ErrNotExist) {
+		return ParseMailmap(strings.NewReader(""))
+	}
+
+	if err != nil {
+		return Mailmap{}, err
+	}
+
+	defer f.Close()
+
+	return ParseMailmap(f)
+}
+
+// ParseMailmap parses mailmap lines:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func ParseMailmap(r io.Reader) (Mailmap, error) {
+	m := Mailmap{
+		byEmail:     map[string]string{},
+		byNameEmail: map[string]string{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+
+		// parts are names before emails and emails: [name, email, name, email]
+		var names, emails []string
+		for {
+			start := strings.Index(line, "<")
+			end := strings.Index(line, ">")
+			if start < 0 || end < start {
+				break
+			}
+
+			names = append(names, strings.TrimSpace(line[:start]))
+			emails = append(emails, strings.TrimSpace(line[start+1:end]))
+			line = line[end+1:]
+		}
+
+		// name only replacement doesn't change email
+		if len(emails) < 2 {
+			continue
+		}
+
+		proper, commitName, commitEmail := emails[0], names[1], strings.ToLower(emails[1])
+
+		if commitName != "" {
+			m.byNameEmail[nameEmailKey(commitName, commitEmail)] = proper
+		} else {
+			m.byEmail[commitEmail] = proper
+		}
+	}
+
+	return m, scanner.Err()
+}
+
+func nameEmailKey(name, email string) string {
+	return strings.ToLower(email) + "\x00" + strings.ToLower(name)
+}
@@ -20,6 +20,7 @@ type LinterError struct {
 	Message  string `xml:"message,attr"`
 	Severity string `xml:"severity,attr"`
 	Source   string `xml:"source,attr"`
+	Rule     string `xml:"-"` // SARIF rule id, checkstyle source is linter name
 }
 
 // ExtractCheckStyleXml парсим xml
@@ -0,0 +1,175 @@
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+)
+
+type sarifFile struct {
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID                   string `json:"id"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifResult struct {
+	RuleID    string `json:"ruleId"`
+	RuleIndex *int   `json:"ruleIndex"`
+	Level     string `json:"level"`
+	Message   struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine   uint `json:"startLine"`
+				StartColumn uint `json:"startColumn"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+}
+
+// ExtractSarif reads SARIF 2.1 report of golangci-lint, semgrep, CodeQL, detekt and other tools.
+// Result is reported in its first location file, results without file location or with not file URI are skipped.
+// Level is result level or rule default level, it is "warning" if both are empty.
+func ExtractSarif(file io.Reader) ([]LinterFile, error) {
+	var data sarifFile
+
+	err := json.NewDecoder(file).Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(data.Version, "2.1") {
+		return nil, fmt.Errorf("unsupported SARIF version %q", data.Version)
+	}
+
+	var result []LinterFile
+
+	files := map[string]int{}
+
+	for _, run := range data.Runs {
+		rules := run.Tool.Driver.Rules
+
+		for _, r := range run.Results {
+			if len(r.Locations) == 0 || r.Locations[0].PhysicalLocation.ArtifactLocation.URI == "" {
+				continue
+			}
+
+			location := r.Locations[0].PhysicalLocation
+
+			path, err := sarifPath(location.ArtifactLocation.URI)
+			if err != nil {
+				log.Println("skip", run.Tool.Driver.Name, "result:", err)
+				continue
+			}
+
+			lintErr := LinterError{
+				Column:   location.Region.StartColumn,
+				Line:     location.Region.StartLine,
+				Message:  r.Message.Text,
+				Severity: r.Level,
+				Source:   run.Tool.Driver.Name,
+				Rule:     r.RuleID,
+			}
+
+			if r.RuleIndex != nil && *r.RuleIndex >= 0 && *r.RuleIndex < len(rules) {
+				rule := rules[*r.RuleIndex]
+				if lintErr.Rule == "" {
+					lintErr.Rule = rule.ID
+				}
+
+				if lintErr.Severity == "" {
+					lintErr.Severity = rule.DefaultConfiguration.Level
+				}
+			}
+
+			if lintErr.Severity == "" {
+				lintErr.Severity = "warning"
+			}
+
+			i, ok := files[path]
+			if !ok {
+				i = len(result)
+				files[path] = i
+				result = append(result, LinterFile{Path: path})
+			}
+
+			result[i].Errors = append(result[i].Errors, lintErr)
+		}
+	}
+
+	return result, nil
+}
+
+// sarifPath returns file path of artifact URI, file URIs are returned as absolute paths.
+// URIs with other schemes, e.g. https or Windows path "C:\src" parsed as "c" scheme, are not supported.
+func sarifPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("artifact uri %q: %w", uri, err)
+	}
+
+	if u.Scheme != "" && u.Scheme != "file" {
+		return "", fmt.Errorf("artifact uri %q: unsupported scheme", uri)
+	}
+
+	return strings.TrimPrefix(u.Path, "./"), nil
+}
+
+// utf8BOM is byte order mark written by Windows tools at the beginning of report
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+// Extract reads checkstyle XML or SARIF report, format is detected by the first report symbol
+func Extract(file io.Reader) ([]LinterFile, error) {
+	reader := bufio.NewReader(file)
+
+	head, err := reader.Peek(len(utf8BOM))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if bytes.Equal(head, utf8BOM) {
+		if _, err := reader.Discard(len(utf8BOM)); err != nil {
+			return nil, err
+		}
+	}
+
+	head, err = reader.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch trimmed := bytes.TrimSpace(head); {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return ExtractSarif(reader)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return ExtractCheckStyleXml(reader)
+	}
+
+	return nil, fmt.Errorf("unknown lint report format, checkstyle XML or SARIF is expected")
+}
@@ -0,0 +1,99 @@
+package lint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractSarif(t *testing.T) {
+	expect := []LinterFile{
+		{
+			Path: "db/create.go",
+			Errors: []LinterError{
+				{Column: 2, Line: 4, Message: "import is not allowed", Severity: "error", Source: "golangci-lint", Rule: "depguard"},
+				{Line: 10, Message: "unchecked error", Severity: "warning", Source: "golangci-lint", Rule: "errcheck"},
+			},
+		},
+		{
+			Path: "/home/user/app/src/main.kt",
+			Errors: []LinterError{
+				{Column: 5, Line: 3, Message: "magic number", Severity: "note", Source: "detekt", Rule: "MagicNumber"},
+			},
+		},
+	}
+
+	result, err := ExtractSarif(bytes.NewBufferString(testSarif))
+	require.NoError(t, err)
+	assert.Equal(t, expect, result)
+
+	_, err = ExtractSarif(bytes.NewBufferString(`{"version": "1.0.0", "runs": []}`))
+	assert.Error(t, err)
+}
+
+func TestExtract(t *testing.T) {
+	result, err := Extract(bytes.NewBufferString("\n  " + testSarif))
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	result, err = Extract(bytes.NewBufferString(testFile))
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	result, err = Extract(bytes.NewBufferString("\xEF\xBB\xBF" + testSarif))
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	_, err = Extract(bytes.NewBufferString("report"))
+	assert.Error(t, err)
+}
+
+const testSarif = `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {"driver": {"name": "golangci-lint", "rules": [{"id": "errcheck"}]}},
+      "results": [
+        {
+          "ruleId": "depguard",
+          "level": "error",
+          "message": {"text": "import is not allowed"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "db/create.go"}, "region": {"startLine": 4, "startColumn": 2}}}]
+        },
+        {
+          "ruleIndex": 0,
+          "message": {"text": "unchecked error"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "./db/create.go"}, "region": {"startLine": 10}}}]
+        },
+        {
+          "ruleId": "typecheck",
+          "message": {"text": "no location"}
+        },
+        {
+          "ruleId": "errcheck",
+          "message": {"text": "windows path"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "C:\\app\\db\\create.go"}, "region": {"startLine": 1}}}]
+        },
+        {
+          "ruleId": "errcheck",
+          "message": {"text": "remote file"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "https://example.com/db/create.go"}, "region": {"startLine": 1}}}]
+        }
+      ]
+    },
+    {
+      "tool": {"driver": {"name": "detekt", "rules": [{"id": "MagicNumber", "defaultConfiguration": {"level": "note"}}]}},
+      "results": [
+        {
+          "ruleId": "MagicNumber",
+          "ruleIndex": 0,
+          "message": {"text": "magic number"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///home/user/app/src/main.kt"}, "region": {"startLine": 3, "startColumn": 5}}}]
+        }
+      ]
+    }
+  ]
+}`
@@ -17,21 +17,52 @@ type Extractors struct {
 	Coverage Extractor[testcoverage.Package]
 }
 
-func NewExtractors() Extractors {
+// Names returns names of not empty extractors
+func (e Extractors) Names() (names []string) {
+	if e.Files != nil {
+		names = append(names, "files")
+	}
+
+	if e.Coverage != nil {
+		names = append(names, "coverage")
+	}
+
+	if e.Git != nil {
+		names = append(names, "git")
+	}
+
+	return names
+}
+
+// NewExtractors returns extractors of project data, files are filtered by project include and exclude rules.
+// Coverage is read from project coverage reports or from detected reports.
+func NewExtractors(p project.Project) Extractors {
+	coverage := testcoverage.Extract
+	if len(p.CoverageReports) > 0 {
+		coverage = testcoverage.ExtractReports(p.CoverageReports)
+	}
+
 	return Extractors{
-		Files:    files.Extract,
+		Files:    files.ExtractWithRules(files.Rules{Include: p.Include, Exclude: p.Exclude}),
 		Git:      git.ExtractCommits,
-		Coverage: testcoverage.ExtractXml,
+		Coverage: coverage,
 	}
 }
 
 func DataEntities() []any {
 	return []any{
 		project.Project{},
+		project.Revision{},
+		project.Package{},
 		project.File{},
+		project.FileSnapshot{},
+		project.Function{},
 		project.Coverage{},
 		project.GitChange{},
 		project.GitCommit{},
+		project.AuthorAlias{},
+		project.TeamMember{},
 		project.LintError{},
+		project.DataFetchJob{},
 	}
 }
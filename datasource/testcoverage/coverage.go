@@ -0,0 +1,357 @@
+package testcoverage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rusinikita/devex/slices"
+)
+
+// Package is coverage of package files, path is relative to project root
+type Package struct {
+	Path  string
+	Files []Coverage
+}
+
+// Coverage is file lines coverage, statements are counted by Go profile only
+type Coverage struct {
+	File              string
+	Percent           uint8 // covered lines percent
+	Statements        uint32
+	CoveredStatements uint32
+	UncoveredLines    []uint32
+	UncoveredRanges   []LineRange
+}
+
+// LineRange is lines from From to To inclusive
+type LineRange struct {
+	From, To uint32
+}
+
+// lineRanges joins sorted lines into ranges of sequential lines
+func lineRanges(lines []uint32) (ranges []LineRange) {
+	for _, l := range lines {
+		if last := len(ranges) - 1; last >= 0 && ranges[last].To+1 == l {
+			ranges[last].To = l
+			continue
+		}
+
+		ranges = append(ranges, LineRange{From: l, To: l})
+	}
+
+	return ranges
+}
+
+// linesCoverage returns file coverage of lines with hit flags
+func linesCoverage(name string, lines map[uint32]bool) Coverage {
+	result := Coverage{File: name}
+
+	covered := 0
+	for l, hit := range lines {
+		if hit {
+			covered++
+		} else {
+			result.UncoveredLines = append(result.UncoveredLines, l)
+		}
+	}
+
+	sort.Slice(result.UncoveredLines, func(i, j int) bool {
+		return result.UncoveredLines[i] < result.UncoveredLines[j]
+	})
+
+	if len(lines) > 0 {
+		result.Percent = uint8(100 * covered / len(lines))
+	}
+
+	result.UncoveredRanges = lineRanges(result.UncoveredLines)
+
+	return result
+}
+
+// sendPackages groups files coverage by project relative file paths into packages ordered by path
+func sendPackages(files map[string]Coverage, c chan<- Package) {
+	packages := map[string]*Package{}
+	for name, coverage := range files {
+		dir := path.Dir(name)
+		if dir == "." {
+			dir = ""
+		}
+
+		p, ok := packages[dir]
+		if !ok {
+			p = &Package{Path: dir}
+			packages[dir] = p
+		}
+
+		p.Files = append(p.Files, coverage)
+	}
+
+	paths := make([]string, 0, len(packages))
+	for p := range packages {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		pkg := packages[p]
+		sort.Slice(pkg.Files, func(i, j int) bool {
+			return pkg.Files[i].File < pkg.Files[j].File
+		})
+
+		c <- *pkg
+	}
+}
+
+const (
+	GoProfileFile = "coverage.out"
+	XmlFile       = "coverage.xml"
+)
+
+const (
+	formatGoProfile = "go"
+	formatCobertura = "cobertura"
+	formatLcov      = "lcov"
+	formatJacoco    = "jacoco"
+)
+
+// reportNames are coverage report file names detected in project folders
+var reportNames = slices.ToSet([]string{
+	GoProfileFile, "cover.out",
+	XmlFile, "cobertura.xml", "cobertura-coverage.xml",
+	"lcov.info",
+	"jacoco.xml", "jacocoTestReport.xml",
+})
+
+// Extract reads coverage reports detected in project folders
+func Extract(ctx context.Context, root string, c chan<- Package) error {
+	reports, err := DetectReports(root)
+	if err != nil {
+		close(c)
+		return err
+	}
+
+	if len(reports) == 0 {
+		close(c)
+		return fmt.Errorf("no coverage reports found in %s", root)
+	}
+
+	return extractReports(ctx, root, reports, c)
+}
+
+// ExtractReports reads reports with paths relative to project root, report format is detected by its content
+func ExtractReports(reports []string) func(ctx context.Context, root string, c chan<- Package) error {
+	return func(ctx context.Context, root string, c chan<- Package) error {
+		return extractReports(ctx, root, reports, c)
+	}
+}
+
+// DetectReports returns project relative paths of known coverage reports with known format.
+// Hidden, vendor and node_modules folders are skipped.
+func DetectReports(root string) (reports []string, err error) {
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p != root && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !reportNames[d.Name()] {
+			return nil
+		}
+
+		format, err := reportFormat(p)
+		if err != nil || format == "" {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		reports = append(reports, filepath.ToSlash(rel))
+
+		return nil
+	})
+
+	return reports, err
+}
+
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules"
+}
+
+// reportFormat detects coverage report format by file beginning
+func reportFormat(report string) (string, error) {
+	f, err := os.Open(report)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	head := make([]byte, 1024)
+
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	head = bytes.TrimSpace(head[:n])
+
+	switch {
+	case bytes.HasPrefix(head, []byte("mode:")):
+		return formatGoProfile, nil
+	case bytes.HasPrefix(head, []byte("TN:")) || bytes.HasPrefix(head, []byte("SF:")):
+		return formatLcov, nil
+	case bytes.Contains(head, []byte("<report")):
+		return formatJacoco, nil
+	case bytes.Contains(head, []byte("<coverage")):
+		return formatCobertura, nil
+	}
+
+	return "", nil
+}
+
+// formatPriority orders reports, Go profile has statements and is the most precise, lcov is the least
+var formatPriority = map[string]int{
+	formatGoProfile: 0,
+	formatJacoco:    1,
+	formatCobertura: 2,
+	formatLcov:      3,
+}
+
+// reportPriority returns report format priority, reports with unknown format are the last ones
+func reportPriority(root, report string) int {
+	if !filepath.IsAbs(report) {
+		report = filepath.Join(root, report)
+	}
+
+	format, err := reportFormat(report)
+	if priority, ok := formatPriority[format]; ok && err == nil {
+		return priority
+	}
+
+	return len(formatPriority)
+}
+
+// extractReports reads reports ordered by format priority.
+// File covered by several reports is taken from the first one, so there is one coverage row per file.
+func extractReports(_ context.Context, root string, reports []string, c chan<- Package) error {
+	defer close(c)
+
+	priorities := map[string]int{}
+	for _, report := range reports {
+		priorities[report] = reportPriority(root, report)
+	}
+
+	reports = append([]string(nil), reports...)
+	sort.SliceStable(reports, func(i, j int) bool {
+		return priorities[reports[i]] < priorities[reports[j]]
+	})
+
+	covered := slices.Set[string]{}
+
+	for _, report := range reports {
+		reportC := make(chan Package)
+		errC := make(chan error, 1)
+
+		go func(report string) {
+			errC <- extractReport(root, report, reportC)
+		}(report)
+
+		for p := range reportC {
+			files := p.Files[:0:0]
+			for _, f := range p.Files {
+				file := path.Join(p.Path, f.File)
+				if covered[file] {
+					continue
+				}
+
+				covered[file] = true
+				files = append(files, f)
+			}
+
+			if len(files) > 0 {
+				p.Files = files
+				c <- p
+			}
+		}
+
+		if err := <-errC; err != nil {
+			return fmt.Errorf("%s coverage report: %w", report, err)
+		}
+	}
+
+	return nil
+}
+
+// extractReport parses report by format, report parsers close channel
+func extractReport(root, report string, c chan<- Package) error {
+	reportPath := report
+	if !filepath.IsAbs(reportPath) {
+		reportPath = filepath.Join(root, report)
+	}
+
+	format, err := reportFormat(reportPath)
+	if err != nil {
+		close(c)
+		return err
+	}
+
+	file, err := os.Open(reportPath)
+	if err != nil {
+		close(c)
+		return err
+	}
+
+	defer file.Close()
+
+	switch format {
+	case formatGoProfile:
+		modules, err := goModules(root)
+		if err != nil {
+			close(c)
+			return err
+		}
+
+		return extractGoProfile(file, modules, c)
+	case formatCobertura:
+		return extractXml(file, c)
+	case formatLcov:
+		reportDir, err := filepath.Rel(root, filepath.Dir(reportPath))
+		if err != nil {
+			close(c)
+			return err
+		}
+
+		return extractLcov(file, lcovSources(root, reportDir), c)
+	case formatJacoco:
+		sources, err := newSourceIndex(root)
+		if err != nil {
+			close(c)
+			return err
+		}
+
+		return extractJacoco(file, sources.path, c)
+	}
+
+	close(c)
+
+	return fmt.Errorf("unknown format")
+}
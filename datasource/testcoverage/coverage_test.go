@@ -0,0 +1,92 @@
+package testcoverage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractReports(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		"go.mod":                  "module github.com/org/repo\n",
+		"coverage.out":            "mode: set\ngithub.com/org/repo/api/api.go:1.1,2.2 1 1\n",
+		"web/src/app.ts":          "",
+		"web/coverage/lcov.info":  "TN:\nSF:src/app.ts\nDA:1,0\nend_of_record\n",
+		"node_modules/lcov.info":  "SF:lib.js\nDA:1,1\nend_of_record\n",
+		"reports/coverage.xml":    "not a report",
+		"reports/custom-lcov.txt": "SF:web/src/app.ts\nDA:1,1\nend_of_record\n",
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0o644))
+	}
+
+	reports, err := DetectReports(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"coverage.out", "web/coverage/lcov.info"}, reports)
+
+	collect := func(extractor func(ctx context.Context, root string, c chan<- Package) error) (result []Package, err error) {
+		c := make(chan Package, 10)
+		err = extractor(context.Background(), root, c)
+
+		for p := range c {
+			result = append(result, p)
+		}
+
+		return result, err
+	}
+
+	result, err := collect(Extract)
+	require.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Path: "api", Files: []Coverage{{File: "api.go", Percent: 100, Statements: 1, CoveredStatements: 1}}},
+		{Path: "web/src", Files: []Coverage{{File: "app.ts", UncoveredLines: []uint32{1}, UncoveredRanges: []LineRange{{From: 1, To: 1}}}}},
+	}, result)
+
+	result, err = collect(ExtractReports([]string{"reports/custom-lcov.txt"}))
+	require.NoError(t, err)
+	assert.Equal(t, []Package{{Path: "web/src", Files: []Coverage{{File: "app.ts", Percent: 100}}}}, result)
+
+	_, err = collect(ExtractReports([]string{"reports/coverage.xml"}))
+	assert.Error(t, err)
+
+	_, err = collect(func(ctx context.Context, _ string, c chan<- Package) error {
+		return Extract(ctx, t.TempDir(), c)
+	})
+	assert.Error(t, err)
+}
+
+func TestExtractReportsDuplicates(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		"go.mod":       "module github.com/org/repo\n",
+		"lcov.info":    "SF:api/api.go\nDA:1,0\nend_of_record\nSF:api/handler.go\nDA:1,1\nend_of_record\n",
+		"coverage.out": "mode: set\ngithub.com/org/repo/api/api.go:1.1,2.2 1 1\n",
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0o644))
+	}
+
+	c := make(chan Package, 10)
+	err := ExtractReports([]string{"lcov.info", "coverage.out"})(context.Background(), root, c)
+	require.NoError(t, err)
+
+	var result []Package
+	for p := range c {
+		result = append(result, p)
+	}
+
+	assert.Equal(t, []Package{
+		{Path: "api", Files: []Coverage{{File: "api.go", Percent: 100, Statements: 1, CoveredStatements: 1}}},
+		{Path: "api", Files: []Coverage{{File: "handler.go", Percent: 100}}},
+	}, result)
+}
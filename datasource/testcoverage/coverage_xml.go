@@ -1,25 +1,11 @@
 package testcoverage
 
 import (
-	"context"
 	"encoding/xml"
 	"io"
-	"os"
-	"path/filepath"
 	"strings"
 )
 
-type Package struct {
-	Path  string
-	Files []Coverage
-}
-
-type Coverage struct {
-	File           string
-	Percent        uint8
-	UncoveredLines []uint32
-}
-
 type xmlFile struct {
 	XMLName  xml.Name `xml:"coverage"`
 	Packages struct {
@@ -70,26 +56,6 @@ func extractXml(file io.Reader, c chan<- Package) error {
 
 	var data xmlFile
 
-	// go test -coverprofile=covcov -covermode=atomic ./...
-	// file:start_line.start_symbol,end_line.end_symbol numberOfStatements hits
-	// считаем hits == 0 и все строки между start_line и end_line
-	/*
-		mode: atomic
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:30.153,38.2 1 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:40.49,53.33 5 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:53.33,56.13 2 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:56.13,59.8 2 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:59.8,61.19 2 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:61.19,62.11 1 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:63.11,65.6 1 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:70.2,72.12 2 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:75.125,80.16 4 5
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:80.16,83.3 2 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:85.2,89.100 4 5
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:89.100,96.3 4 0
-		go.avito.ru/msg/service-seller-audience/internal/actions/send_discounts/action.go:98.2,109.16 2 5
-	*/
-
 	err := xml.NewDecoder(file).Decode(&data)
 	if err != nil {
 		return err
@@ -104,9 +70,10 @@ func extractXml(file io.Reader, c chan<- Package) error {
 			percent, uncovered := class.lines()
 
 			p.Files = append(p.Files, Coverage{
-				File:           class.Name,
-				Percent:        percent,
-				UncoveredLines: uncovered,
+				File:            class.Name,
+				Percent:         percent,
+				UncoveredLines:  uncovered,
+				UncoveredRanges: lineRanges(uncovered),
 			})
 		}
 
@@ -115,15 +82,3 @@ func extractXml(file io.Reader, c chan<- Package) error {
 
 	return err
 }
-
-func ExtractXml(_ context.Context, path string, c chan<- Package) error {
-	file := filepath.Join(path, "coverage.xml")
-
-	content, err := os.Open(file)
-	if err != nil {
-		close(c)
-		return err
-	}
-
-	return extractXml(content, c)
-}
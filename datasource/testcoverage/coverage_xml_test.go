@@ -33,6 +33,8 @@ func TestExtractXml(t *testing.T) {
 		},
 	}
 
+	expect[1].Files[0].UncoveredRanges = lineRanges(expect[1].Files[0].UncoveredLines)
+
 	c := make(chan Package, 5)
 
 	err := extractXml(bytes.NewBufferString(testFile), c)
@@ -53,7 +55,7 @@ func TestDebug(t *testing.T) {
 	wg, ctx := errgroup.WithContext(context.TODO())
 
 	wg.Go(func() error {
-		return ExtractXml(ctx, "/Users/nvrusin/black", c)
+		return Extract(ctx, "/Users/nvrusin/black", c)
 	})
 
 	wg.Go(func() error {
@@ -0,0 +1,194 @@
+package testcoverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// profileBlock is statements block of Go coverage profile
+type profileBlock struct {
+	StartLine, EndLine uint32
+	Statements         uint32
+	Count              uint64
+}
+
+// extractGoProfile reads "go test -coverprofile" file, e.g.:
+//
+//	mode: atomic
+//	github.com/org/repo/internal/send/action.go:30.153,38.2 1 0
+//	github.com/org/repo/internal/send/action.go:40.49,53.33 5 2
+//
+// Block is file:start_line.start_column,end_line.end_column statements_count hits_count.
+// Import paths are mapped to project files by modules, module path to module folder.
+func extractGoProfile(file io.Reader, modules map[string]string, c chan<- Package) error {
+	defer close(c)
+
+	// the same block is repeated if profiles of several test binaries are merged
+	blocks := map[string]map[[2]uint32]profileBlock{}
+
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		name, block, err := parseProfileLine(line)
+		if err != nil {
+			return fmt.Errorf("coverage profile line %d: %w", number, err)
+		}
+
+		name = profileFilePath(name, modules)
+		if blocks[name] == nil {
+			blocks[name] = map[[2]uint32]profileBlock{}
+		}
+
+		key := [2]uint32{block.StartLine, block.EndLine}
+		if prev, ok := blocks[name][key]; ok && prev.Count > block.Count {
+			block.Count = prev.Count
+		}
+
+		blocks[name][key] = block
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	files := map[string]Coverage{}
+	for name, fileBlocks := range blocks {
+		files[name] = blocksCoverage(path.Base(name), fileBlocks)
+	}
+
+	sendPackages(files, c)
+
+	return nil
+}
+
+func parseProfileLine(line string) (name string, block profileBlock, err error) {
+	i := strings.LastIndex(line, ":")
+	if i < 0 {
+		return "", block, fmt.Errorf("no file name in %q", line)
+	}
+
+	var startColumn, endColumn uint32
+
+	_, err = fmt.Sscanf(line[i+1:], "%d.%d,%d.%d %d %d",
+		&block.StartLine, &startColumn, &block.EndLine, &endColumn, &block.Statements, &block.Count)
+	if err != nil {
+		return "", block, fmt.Errorf("%q block: %w", line, err)
+	}
+
+	return line[:i], block, nil
+}
+
+// profileFilePath returns project relative file path, the longest module path prefix is replaced by module folder
+func profileFilePath(name string, modules map[string]string) string {
+	module := ""
+	for m := range modules {
+		if (name == m || strings.HasPrefix(name, m+"/")) && len(m) > len(module) {
+			module = m
+		}
+	}
+
+	if module == "" {
+		return name
+	}
+
+	return path.Join(modules[module], strings.TrimPrefix(name, module+"/"))
+}
+
+// blocksCoverage returns statements and lines coverage, line is covered if any block with line has hits
+func blocksCoverage(name string, blocks map[[2]uint32]profileBlock) Coverage {
+	var statements, covered uint32
+
+	lines := map[uint32]bool{}
+	for _, b := range blocks {
+		statements += b.Statements
+		if b.Count > 0 {
+			covered += b.Statements
+		}
+
+		for l := b.StartLine; l <= b.EndLine; l++ {
+			lines[l] = lines[l] || b.Count > 0
+		}
+	}
+
+	result := linesCoverage(name, lines)
+	result.Statements, result.CoveredStatements = statements, covered
+
+	return result
+}
+
+// goModules returns module paths of project go.mod files with module folders relative to project root
+func goModules(projectPath string) (map[string]string, error) {
+	modules := map[string]string{}
+
+	err := filepath.WalkDir(projectPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p != projectPath && (skipDir(d.Name()) || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.Name() != "go.mod" {
+			return nil
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		module := modulePath(content)
+		if module == "" {
+			return nil
+		}
+
+		dir, err := filepath.Rel(projectPath, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+
+		if dir == "." {
+			dir = ""
+		}
+
+		modules[module] = filepath.ToSlash(dir)
+
+		return nil
+	})
+
+	return modules, err
+}
+
+// modulePath returns go.mod module directive path
+func modulePath(goMod []byte) string {
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+
+		if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+			return unquoted
+		}
+
+		return fields[1]
+	}
+
+	return ""
+}
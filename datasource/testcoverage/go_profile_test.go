@@ -0,0 +1,70 @@
+package testcoverage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractGoProfile(t *testing.T) {
+	profile := `mode: atomic
+github.com/org/repo/internal/send/action.go:3.20,5.2 2 0
+github.com/org/repo/internal/send/action.go:7.20,9.12 2 3
+github.com/org/repo/internal/send/action.go:9.12,11.3 1 0
+github.com/org/repo/internal/send/action.go:3.20,5.2 2 1
+github.com/org/repo/internal/send/action.go:13.2,14.3 1 0
+github.com/org/repo/main.go:1.1,2.2 1 1
+github.com/org/repo/tools/gen/gen.go:1.1,4.2 4 0
+`
+
+	modules := map[string]string{
+		"github.com/org/repo":           "",
+		"github.com/org/repo/tools/gen": "tools",
+	}
+
+	c := make(chan Package, 5)
+	require.NoError(t, extractGoProfile(bytes.NewBufferString(profile), modules, c))
+
+	var result []Package
+	for p := range c {
+		result = append(result, p)
+	}
+
+	assert.Equal(t, []Package{
+		{Path: "", Files: []Coverage{{File: "main.go", Percent: 100, Statements: 1, CoveredStatements: 1}}},
+		{Path: "internal/send", Files: []Coverage{{
+			File:              "action.go",
+			Percent:           60,
+			Statements:        6,
+			CoveredStatements: 4,
+			UncoveredLines:    []uint32{10, 11, 13, 14},
+			UncoveredRanges:   []LineRange{{From: 10, To: 11}, {From: 13, To: 14}},
+		}}},
+		{Path: "tools", Files: []Coverage{{
+			File:            "gen.go",
+			Statements:      4,
+			UncoveredLines:  []uint32{1, 2, 3, 4},
+			UncoveredRanges: []LineRange{{From: 1, To: 4}},
+		}}},
+	}, result)
+
+	err := extractGoProfile(bytes.NewBufferString("mode: set\nmain.go:1.1 1"), modules, make(chan Package, 1))
+	assert.Error(t, err)
+}
+
+func TestGoModules(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "tools", "gen"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "vendor", "lib"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/org/repo\n\ngo 1.20\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "tools", "gen", "go.mod"), []byte(`module "github.com/org/gen"`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "lib", "go.mod"), []byte("module lib"), 0o644))
+
+	modules, err := goModules(root)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"github.com/org/repo": "", "github.com/org/gen": "tools/gen"}, modules)
+}
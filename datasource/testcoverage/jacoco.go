@@ -0,0 +1,134 @@
+package testcoverage
+
+import (
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rusinikita/devex/slices"
+)
+
+type jacocoReport struct {
+	XMLName xml.Name `xml:"report"`
+	jacocoGroup
+}
+
+// jacocoGroup is multi-module report group, groups can be nested
+type jacocoGroup struct {
+	Groups   []jacocoGroup   `xml:"group"`
+	Packages []jacocoPackage `xml:"package"`
+}
+
+func (g jacocoGroup) packages() []jacocoPackage {
+	packages := g.Packages
+	for _, group := range g.Groups {
+		packages = append(packages, group.packages()...)
+	}
+
+	return packages
+}
+
+type jacocoPackage struct {
+	Name        string `xml:"name,attr"` // slash separated, e.g. "com/example/app"
+	SourceFiles []struct {
+		Name  string       `xml:"name,attr"`
+		Lines []jacocoLine `xml:"line"`
+	} `xml:"sourcefile"`
+}
+
+// jacocoLine is line with missed and covered instructions
+type jacocoLine struct {
+	Number              uint32 `xml:"nr,attr"`
+	MissedInstructions  uint32 `xml:"mi,attr"`
+	CoveredInstructions uint32 `xml:"ci,attr"`
+}
+
+// extractJacoco reads JaCoCo XML report, line is covered if any of its instructions is covered.
+// Package source files are mapped to project files by resolve.
+func extractJacoco(file io.Reader, resolve func(pkg, name string) string, c chan<- Package) error {
+	defer close(c)
+
+	var report jacocoReport
+
+	err := xml.NewDecoder(file).Decode(&report)
+	if err != nil {
+		return err
+	}
+
+	files := map[string]Coverage{}
+
+	for _, p := range report.packages() {
+		for _, source := range p.SourceFiles {
+			lines := map[uint32]bool{}
+			for _, l := range source.Lines {
+				lines[l.Number] = lines[l.Number] || l.CoveredInstructions > 0
+			}
+
+			files[resolve(p.Name, source.Name)] = linesCoverage(source.Name, lines)
+		}
+	}
+
+	sendPackages(files, c)
+
+	return nil
+}
+
+var jvmExtensions = slices.ToSet([]string{".java", ".kt", ".kts", ".scala", ".groovy"})
+
+// sourceIndex is project relative paths of JVM source files by file name
+type sourceIndex map[string][]string
+
+func newSourceIndex(root string) (sourceIndex, error) {
+	index := sourceIndex{}
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p != root && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !jvmExtensions[filepath.Ext(p)] {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		index[d.Name()] = append(index[d.Name()], filepath.ToSlash(rel))
+
+		return nil
+	})
+
+	return index, err
+}
+
+// path returns project file of package source file, the shortest source root is used if several files match.
+// Package path is returned if project has no such file.
+func (s sourceIndex) path(pkg, name string) string {
+	file := path.Join(pkg, name)
+
+	found := ""
+	for _, p := range s[name] {
+		if (p == file || strings.HasSuffix(p, "/"+file)) && (found == "" || len(p) < len(found)) {
+			found = p
+		}
+	}
+
+	if found == "" {
+		return file
+	}
+
+	return found
+}
@@ -0,0 +1,82 @@
+package testcoverage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractJacoco(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="app">
+  <sessioninfo id="local" start="1" dump="2"/>
+  <group name="core">
+    <package name="com/example/core">
+      <class name="com/example/core/Store" sourcefilename="Store.kt"/>
+      <sourcefile name="Store.kt">
+        <line nr="3" mi="0" ci="4" mb="0" cb="0"/>
+        <line nr="4" mi="2" ci="0" mb="0" cb="0"/>
+        <line nr="5" mi="1" ci="0" mb="0" cb="0"/>
+        <line nr="8" mi="1" ci="1" mb="1" cb="1"/>
+        <counter type="LINE" missed="2" covered="2"/>
+      </sourcefile>
+    </package>
+  </group>
+  <package name="com/example/app">
+    <sourcefile name="Main.java">
+      <line nr="10" mi="0" ci="3"/>
+    </sourcefile>
+  </package>
+</report>
+`
+
+	sources := sourceIndex{
+		"Store.kt": {"core/src/main/kotlin/com/example/core/Store.kt", "core/build/generated/com/example/core/Store.kt/copy"},
+	}
+
+	c := make(chan Package, 5)
+	require.NoError(t, extractJacoco(bytes.NewBufferString(report), sources.path, c))
+
+	var result []Package
+	for p := range c {
+		result = append(result, p)
+	}
+
+	assert.Equal(t, []Package{
+		{Path: "com/example/app", Files: []Coverage{{File: "Main.java", Percent: 100}}},
+		{Path: "core/src/main/kotlin/com/example/core", Files: []Coverage{{
+			File:            "Store.kt",
+			Percent:         50,
+			UncoveredLines:  []uint32{4, 5},
+			UncoveredRanges: []LineRange{{From: 4, To: 5}},
+		}}},
+	}, result)
+}
+
+func TestSourceIndex(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"app/src/main/java/com/example/Main.java",
+		"app/src/test/java/com/example/MainTest.java",
+		"node_modules/lib/com/example/Main.java",
+		"app/README.md",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), nil, 0o644))
+	}
+
+	index, err := newSourceIndex(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, sourceIndex{
+		"Main.java":     {"app/src/main/java/com/example/Main.java"},
+		"MainTest.java": {"app/src/test/java/com/example/MainTest.java"},
+	}, index)
+	assert.Equal(t, "app/src/main/java/com/example/Main.java", index.path("com/example", "Main.java"))
+	assert.Equal(t, "org/Other.java", index.path("org", "Other.java"))
+}
@@ -0,0 +1,137 @@
+package testcoverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// extractLcov reads LCOV tracefile records, only line hits are used:
+//
+//	SF:src/app.ts
+//	DA:3,1
+//	DA:4,0
+//	end_of_record
+//
+// Source file paths are mapped to project files by resolve, records of sources outside of project are skipped.
+func extractLcov(file io.Reader, resolve func(source string) (string, bool), c chan<- Package) error {
+	defer close(c)
+
+	// the same source file is repeated for each test name
+	files := map[string]map[uint32]bool{}
+
+	var lines map[uint32]bool
+
+	// skip is true for records of not project sources
+	skip := false
+
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		key, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+
+		switch key {
+		case "SF":
+			name, ok := resolve(value)
+			if skip = !ok; skip {
+				log.Println("skip lcov source outside of project:", value)
+				continue
+			}
+
+			if files[name] == nil {
+				files[name] = map[uint32]bool{}
+			}
+
+			lines = files[name]
+		case "DA":
+			if skip {
+				continue
+			}
+
+			if lines == nil {
+				return fmt.Errorf("lcov line %d: DA record without SF record", number)
+			}
+
+			fields := strings.Split(value, ",")
+			if len(fields) < 2 {
+				return fmt.Errorf("lcov line %d: DA record %q", number, value)
+			}
+
+			line, err := strconv.ParseUint(fields[0], 10, 32)
+			if err != nil {
+				return fmt.Errorf("lcov line %d: %w", number, err)
+			}
+
+			hits, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("lcov line %d: %w", number, err)
+			}
+
+			lines[uint32(line)] = lines[uint32(line)] || hits > 0
+		case "end_of_record":
+			lines, skip = nil, false
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	coverage := map[string]Coverage{}
+	for name, fileLines := range files {
+		coverage[name] = linesCoverage(path.Base(name), fileLines)
+	}
+
+	sendPackages(coverage, c)
+
+	return nil
+}
+
+// lcovSources returns project relative path of LCOV source file, it is false for sources outside of project root.
+// Relative source paths are relative to test runner folder, it is report folder or one of its parents with source file.
+func lcovSources(root, reportDir string) func(source string) (string, bool) {
+	return func(source string) (string, bool) {
+		source = filepath.FromSlash(source)
+
+		if filepath.IsAbs(source) {
+			rel, err := filepath.Rel(root, source)
+			if err != nil || outsideRoot(rel) {
+				return "", false
+			}
+
+			return filepath.ToSlash(rel), true
+		}
+
+		for dir := reportDir; ; dir = filepath.Dir(dir) {
+			candidate := filepath.Join(dir, source)
+			if outsideRoot(candidate) {
+				break
+			}
+
+			if _, err := os.Stat(filepath.Join(root, candidate)); err == nil {
+				return filepath.ToSlash(candidate), true
+			}
+
+			if dir == "." || dir == string(filepath.Separator) {
+				break
+			}
+		}
+
+		source = filepath.Clean(source)
+		if outsideRoot(source) {
+			return "", false
+		}
+
+		return filepath.ToSlash(source), true
+	}
+}
+
+// outsideRoot checks if root relative path points outside of root
+func outsideRoot(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,80 @@
+package testcoverage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLcov(t *testing.T) {
+	report := `TN:unit
+SF:src/app.ts
+FN:1,main
+DA:1,1
+DA:2,0
+DA:3,0
+DA:5,2
+LF:4
+LH:2
+end_of_record
+TN:e2e
+SF:src/app.ts
+DA:2,1
+end_of_record
+SF:index.js
+DA:1,0
+end_of_record
+SF:/usr/lib/node/lib.js
+DA:1,1
+end_of_record
+`
+
+	c := make(chan Package, 5)
+	require.NoError(t, extractLcov(bytes.NewBufferString(report), func(source string) (string, bool) {
+		return "web/" + source, !filepath.IsAbs(source)
+	}, c))
+
+	var result []Package
+	for p := range c {
+		result = append(result, p)
+	}
+
+	assert.Equal(t, []Package{
+		{Path: "web", Files: []Coverage{{File: "index.js", UncoveredLines: []uint32{1}, UncoveredRanges: []LineRange{{From: 1, To: 1}}}}},
+		{Path: "web/src", Files: []Coverage{{File: "app.ts", Percent: 75, UncoveredLines: []uint32{3}, UncoveredRanges: []LineRange{{From: 3, To: 3}}}}},
+	}, result)
+
+	err := extractLcov(bytes.NewBufferString("DA:1,1\n"), func(source string) (string, bool) {
+		return source, true
+	}, make(chan Package, 1))
+	assert.Error(t, err)
+}
+
+func TestLcovSources(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "web", "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "web", "src", "app.ts"), nil, 0o644))
+
+	sources := lcovSources(root, filepath.Join("web", "coverage"))
+
+	source := func(s string) string {
+		p, ok := sources(s)
+		assert.True(t, ok, s)
+
+		return p
+	}
+
+	assert.Equal(t, "web/src/app.ts", source("src/app.ts"))
+	assert.Equal(t, "web/src/app.ts", source(filepath.Join(root, "web", "src", "app.ts")))
+	assert.Equal(t, "lib/missing.ts", source("./lib/missing.ts"))
+
+	_, ok := sources(filepath.Join(filepath.Dir(root), "other", "app.ts"))
+	assert.False(t, ok)
+
+	_, ok = sources("../../../outside.ts")
+	assert.False(t, ok)
+}
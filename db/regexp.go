@@ -0,0 +1,68 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"sync"
+
+	sqlitedriver "github.com/glebarez/go-sqlite"
+)
+
+// regexpCacheSize limits cached patterns, filters are user input of long-running server
+const regexpCacheSize = 256
+
+// regexpCache keeps compiled patterns, sqlite calls function for every row.
+// Cache is cleared when it is full, so patterns of recent queries are compiled once again.
+type regexpCache struct {
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func (c *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if re, ok := c.patterns[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.patterns == nil || len(c.patterns) >= regexpCacheSize {
+		c.patterns = map[string]*regexp.Regexp{}
+	}
+
+	c.patterns[pattern] = re
+
+	return re, nil
+}
+
+var regexps regexpCache
+
+// regexp function makes sqlite "X REGEXP Y" operator available, it is used by dashboard filters
+func init() {
+	sqlitedriver.MustRegisterDeterministicScalarFunction("regexp", 2, func(_ *sqlitedriver.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+
+		re, err := regexps.compile(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		return re.MatchString(valueString(args[1])), nil
+	})
+}
+
+func valueString(v driver.Value) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+
+	return fmt.Sprint(v)
+}
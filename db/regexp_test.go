@@ -0,0 +1,24 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexpCache(t *testing.T) {
+	var cache regexpCache
+
+	for i := 0; i < regexpCacheSize*2; i++ {
+		re, err := cache.compile(fmt.Sprintf("^%d$", i))
+		require.NoError(t, err)
+		assert.True(t, re.MatchString(fmt.Sprint(i)))
+	}
+
+	assert.LessOrEqual(t, len(cache.patterns), regexpCacheSize)
+
+	_, err := cache.compile("(")
+	assert.Error(t, err)
+}
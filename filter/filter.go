@@ -0,0 +1,248 @@
+/*
+Package filter parses dashboard filter expressions into parameterized SQL conditions.
+
+Expression syntax:
+  - ';' separates conditions joined with AND;
+  - ',' separates condition alternatives joined with OR;
+  - '!' prefix negates term;
+  - '=' prefix is exact match, '~' prefix is regular expression match;
+  - term with '*', '?' or '[' is glob match, other terms are substring match;
+  - '\' escapes special symbols, spaces around terms are trimmed.
+
+Example: "internal,pkg;!generated;~_test\.go$".
+*/
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Operator int
+
+const (
+	Contains Operator = iota
+	Exact
+	Glob
+	Regex
+)
+
+// Term is a single filter value with match operator
+type Term struct {
+	Value    string
+	Operator Operator
+	Negate   bool
+}
+
+// Expr is AND list of OR terms lists
+type Expr [][]Term
+
+// SQL is parameterized SQL condition
+type SQL struct {
+	Query string
+	Vars  []any
+}
+
+// And returns condition joined with AND operator, empty conditions are skipped
+func (s SQL) And(other SQL) SQL {
+	if other.Query == "" {
+		return s
+	}
+
+	if s.Query == "" {
+		return other
+	}
+
+	return SQL{
+		Query: s.Query + " and " + other.Query,
+		Vars:  append(append([]any{}, s.Vars...), other.Vars...),
+	}
+}
+
+// Prefixed returns condition with " and " prefix for appending to where clause. Empty condition stays empty.
+func (s SQL) Prefixed() SQL {
+	if s.Query == "" {
+		return s
+	}
+
+	return SQL{Query: " and " + s.Query, Vars: s.Vars}
+}
+
+var ErrSyntax = errors.New("filter syntax error")
+
+// Parse parses filter expression. Empty expression matches everything.
+func Parse(s string) (expr Expr, err error) {
+	trimmed := strings.TrimRight(s, `\`)
+	if (len(s)-len(trimmed))%2 == 1 {
+		return nil, fmt.Errorf("%w: %q: unfinished escape", ErrSyntax, s)
+	}
+
+	for _, group := range split(s, ';') {
+		var terms []Term
+
+		for _, part := range split(group, ',') {
+			term, err := parseTerm(part)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %s", ErrSyntax, s, err)
+			}
+
+			if term.Value == "" {
+				continue
+			}
+
+			terms = append(terms, term)
+		}
+
+		if len(terms) > 0 {
+			expr = append(expr, terms)
+		}
+	}
+
+	return expr, nil
+}
+
+// split splits string by not escaped separator, escaping is kept for term parsing
+func split(s string, sep byte) (parts []string) {
+	start := 0
+	escaped := false
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+func parseTerm(s string) (t Term, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return t, nil
+	}
+
+	if strings.HasPrefix(s, "!") {
+		t.Negate = true
+		s = s[1:]
+	}
+
+	switch {
+	case strings.HasPrefix(s, "="):
+		t.Operator = Exact
+		s = s[1:]
+	case strings.HasPrefix(s, "~"):
+		t.Operator = Regex
+		s = s[1:]
+	case strings.ContainsAny(unescape(s), "*?["):
+		t.Operator = Glob
+	}
+
+	if t.Operator == Regex {
+		// regex keeps escaping except separators
+		t.Value = strings.NewReplacer(`\,`, ",", `\;`, ";").Replace(s)
+
+		if _, err := regexp.Compile(t.Value); err != nil {
+			return t, err
+		}
+	} else {
+		t.Value = unescape(s)
+	}
+
+	if t.Value == "" && (t.Negate || t.Operator != Contains) {
+		return t, errors.New("empty term")
+	}
+
+	return t, nil
+}
+
+func unescape(s string) string {
+	var b strings.Builder
+
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+
+		escaped = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SQL returns parameterized condition for column
+func (e Expr) SQL(column string) SQL {
+	var (
+		groups []string
+		vars   []any
+	)
+
+	for _, terms := range e {
+		var parts []string
+
+		for _, t := range terms {
+			sql, v := t.sql(column)
+			parts = append(parts, sql)
+			vars = append(vars, v)
+		}
+
+		sql := strings.Join(parts, " or ")
+		if len(parts) > 1 {
+			sql = "(" + sql + ")"
+		}
+
+		groups = append(groups, sql)
+	}
+
+	return SQL{
+		Query: strings.Join(groups, " and "),
+		Vars:  vars,
+	}
+}
+
+func (t Term) sql(column string) (string, any) {
+	not := ""
+	if t.Negate {
+		not = " not"
+	}
+
+	switch t.Operator {
+	case Exact:
+		if t.Negate {
+			return column + " != ?", t.Value
+		}
+
+		return column + " = ?", t.Value
+	case Glob:
+		return column + not + " glob ?", t.Value
+	case Regex:
+		return column + not + " regexp ?", t.Value
+	default:
+		return column + not + ` like ? escape '\'`, "%" + likeEscaper.Replace(t.Value) + "%"
+	}
+}
+
+// Values returns not negated terms values
+func (e Expr) Values() (values []string) {
+	for _, terms := range e {
+		for _, t := range terms {
+			if !t.Negate {
+				values = append(values, t.Value)
+			}
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		filter string
+		expect SQL
+	}{
+		{
+			filter: "bla,!opa;dich",
+			expect: SQL{
+				Query: `(name like ? escape '\' or name not like ? escape '\') and name like ? escape '\'`,
+				Vars:  []any{"%bla%", "%opa%", "%dich%"},
+			},
+		},
+		{
+			filter: "",
+			expect: SQL{},
+		},
+		{
+			filter: " =main.go , !=doc.go ;",
+			expect: SQL{
+				Query: `(name = ? or name != ?)`,
+				Vars:  []any{"main.go", "doc.go"},
+			},
+		},
+		{
+			filter: `*.go;!*_test.go;~^[a-z]+\.go$`,
+			expect: SQL{
+				Query: `name glob ? and name not glob ? and name regexp ?`,
+				Vars:  []any{"*.go", "*_test.go", `^[a-z]+\.go$`},
+			},
+		},
+		{
+			filter: `it's;100%\,_;~a{1\,2}`,
+			expect: SQL{
+				Query: `name like ? escape '\' and name like ? escape '\' and name regexp ?`,
+				Vars:  []any{"%it's%", `%100\%,\_%`, "a{1,2}"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.filter, func(t *testing.T) {
+			expr, err := Parse(test.filter)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expect, expr.SQL("name"))
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, filter := range []string{"!", "a,=", "~(", `a\`, "~"} {
+		_, err := Parse(filter)
+		assert.ErrorIs(t, err, ErrSyntax, filter)
+	}
+}
+
+func TestValues(t *testing.T) {
+	expr, err := Parse("todo,!nolint;billing")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"todo", "billing"}, expr.Values())
+}
@@ -6,6 +6,7 @@ require (
 	github.com/acheong08/cybertron v0.1.3-0.20230526061827-bda4623885f7
 	github.com/acheong08/vectordb v0.0.0-20230527163032-83ee790411cd
 	github.com/gin-gonic/gin v1.9.0
+	github.com/glebarez/go-sqlite v1.21.1
 	github.com/glebarez/sqlite v1.8.0
 	github.com/go-echarts/go-echarts/v2 v2.2.6
 	github.com/go-git/go-git/v5 v5.6.1
@@ -13,6 +14,7 @@ require (
 	github.com/rs/zerolog v1.27.0
 	github.com/stretchr/testify v1.8.3
 	golang.org/x/sync v0.2.0
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/gorm v1.25.2-0.20230530020048-26663ab9bf55
 )
 
@@ -32,7 +34,6 @@ require (
 	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/emirpasic/gods v1.18.1 // indirect
 	github.com/gin-contrib/sse v0.1.0 // indirect
-	github.com/glebarez/go-sqlite v1.21.1 // indirect
 	github.com/go-git/gcfg v1.5.0 // indirect
 	github.com/go-git/go-billy/v5 v5.4.1 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
@@ -86,7 +87,6 @@ require (
 	golang.org/x/tools v0.9.1 // indirect
 	google.golang.org/protobuf v1.30.0 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	modernc.org/libc v1.22.3 // indirect
 	modernc.org/mathutil v1.5.0 // indirect
 	modernc.org/memory v1.5.0 // indirect
@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -18,6 +20,10 @@ import (
 
 var tags = flag.String("tags", "", "file content tags")
 var lang = flag.String("lang", "go", "main project language")
+var include = flag.String("include", "", "comma separated files patterns in .gitignore syntax, only matched files are collected")
+var exclude = flag.String("exclude", "", "comma separated files patterns in .gitignore syntax, e.g. 'vendor/,*.pb.go'")
+var coverage = flag.String("coverage", "", "comma separated coverage report paths relative to project folder, reports are detected if empty")
+var commit = flag.String("commit", "", "commit hash of imported coverage report")
 
 func main() {
 	flag.Parse()
@@ -37,66 +43,203 @@ func main() {
 		path := flag.Arg(2)
 
 		p := project.Project{
-			Alias:      alias,
-			Language:   *lang,
-			FolderPath: path,
-			CreatedAt:  time.Now(),
+			Alias:           alias,
+			Language:        *lang,
+			FolderPath:      path,
+			Include:         splitFlag(*include),
+			Exclude:         splitFlag(*exclude),
+			CoverageReports: splitFlag(*coverage),
+			CreatedAt:       time.Now(),
 		}
 
 		log.Println("Creating project in", path)
 
-		projectResult := data.FirstOrCreate(&p, p)
-		err := projectResult.Error
+		err := datacollector.CreateProject(data, &p)
 		if err != nil {
-			log.Fatal("db error", err)
-		}
-
-		if projectResult.RowsAffected == 0 {
-			log.Fatal(alias, " already exists, please use 'update' command")
+			log.Fatal("project error ", err)
 		}
 
 		if len(*tags) > 0 {
 			files.Tags = append(files.Tags, strings.Split(*tags, ",")...)
 		}
 
-		err = datacollector.Collect(context.TODO(), data, p, datasource.NewExtractors())
+		err = datacollector.Collect(context.TODO(), data, p, datasource.NewExtractors(p))
 		if err != nil {
 			log.Fatal("collect error ", err)
 		}
 
 	case "update":
-		log.Fatal("not implemented, please remove devex_db")
-
-		// p := project.Project{Alias: alias}
-
-		// err := data.Take(&p).Error
-		// if err != nil {
-		// 	log.Fatal("db error", err)
-		// }
-		//
-		// err = data.Delete(p, p).Error
-		// if err != nil {
-		// 	log.Fatal("db error", err)
-		// }
-		//
-		// p.ID = 0
-		// err = data.Create(&p).Error
-		// if err != nil {
-		// 	log.Fatal("db error", err)
-		// }
-
-		// err = datacollector.Collect(context.TODO(), data, p, datasource.NewExtractors())
-		// if err != nil {
-		// 	log.Fatal("collect error", err)
-		// }
+		p := project.Project{}
+
+		err := data.Take(&p, "alias = ?", alias).Error
+		if err != nil {
+			log.Fatal("db error ", err)
+		}
+
+		if len(*tags) > 0 {
+			files.Tags = append(files.Tags, strings.Split(*tags, ",")...)
+		}
+
+		// rules are saved, so next updates and dashboard jobs use them too
+		if *include != "" || *exclude != "" {
+			p.Include, p.Exclude = splitFlag(*include), splitFlag(*exclude)
+
+			err = data.Select("include", "exclude").Updates(&p).Error
+			if err != nil {
+				log.Fatal("db error ", err)
+			}
+		}
+
+		if *coverage != "" {
+			p.CoverageReports = splitFlag(*coverage)
+
+			err = data.Select("coverage_reports").Updates(&p).Error
+			if err != nil {
+				log.Fatal("db error ", err)
+			}
+		}
+
+		err = datacollector.Update(context.TODO(), data, p)
+		if err != nil {
+			log.Fatal("collect error ", err)
+		}
 
 	case "server":
 		err := dashboard.RunServer(data)
 		if err != nil {
 			log.Fatal("server", err)
 		}
+	case "report":
+		reportFlags := flag.NewFlagSet("report", flag.ExitOnError)
+		output := reportFlags.String("o", "report.html", "report file path")
+		query := reportFlags.String("params", "", "dashboard URL query params, e.g. 'per_files=true&name_filter=*.go'")
+
+		// flags are allowed between and after project aliases
+		var aliases []string
+		for args := flag.Args()[1:]; len(args) > 0; {
+			_ = reportFlags.Parse(args)
+
+			args = reportFlags.Args()
+			if len(args) > 0 {
+				aliases = append(aliases, args[0])
+				args = args[1:]
+			}
+		}
+
+		params, err := dashboard.ReportParams(data, aliases, *query)
+		if err != nil {
+			log.Fatal("report params ", err)
+		}
+
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal("report file ", err)
+		}
+
+		err = dashboard.RenderReport(context.TODO(), data, params, f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			log.Fatal("report error ", err)
+		}
+
+		log.Println("report saved to", *output)
 	case "version":
 		println("v0.1")
+	case "priority":
+		if flag.NArg() < 4 {
+			log.Fatal("usage: devex priority {{project slug}} {{priority}} {{package glob}}...")
+		}
+
+		priority, err := project.ParsePriority(flag.Arg(2))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		updated, err := datacollector.SetPriority(data, alias, priority, flag.Args()[3:])
+		if err != nil {
+			log.Fatal("priority error ", err)
+		}
+
+		log.Println(updated, "packages marked as", priority)
+	case "author":
+		if flag.NArg() < 3 {
+			log.Fatal("usage: devex author {{author email}} {{canonical author email}}")
+		}
+
+		err := datacollector.SetAuthorAlias(data, flag.Arg(1), flag.Arg(2))
+		if err != nil {
+			log.Fatal("author error ", err)
+		}
+
+		log.Println(flag.Arg(1), "merged into", flag.Arg(2))
+	case "teams":
+		f, err := os.Open(flag.Arg(1))
+		if err != nil {
+			log.Fatal("usage: devex teams {{teams.yaml}}, error ", err)
+		}
+
+		members, err := datacollector.SetTeams(data, f)
+		f.Close()
+		if err != nil {
+			log.Fatal("teams error ", err)
+		}
+
+		log.Println(members, "team members saved")
+	case "architecture":
+		if flag.NArg() < 2 {
+			log.Fatal("usage: devex architecture {{project slug}} [{{rules.yaml}}]")
+		}
+
+		// rules are saved, so dashboard checks them too
+		if flag.NArg() > 2 {
+			f, err := os.Open(flag.Arg(2))
+			if err != nil {
+				log.Fatal("rules file ", err)
+			}
+
+			rules, err := datacollector.SetImportRules(data, alias, f)
+			f.Close()
+			if err != nil {
+				log.Fatal("rules error ", err)
+			}
+
+			log.Println(rules, "import rules saved")
+		}
+
+		violations, err := dashboard.CheckArchitecture(data, alias)
+		if err != nil {
+			log.Fatal("architecture error ", err)
+		}
+
+		for _, v := range violations {
+			fmt.Println(v)
+		}
+
+		if len(violations) > 0 {
+			log.Println(len(violations), "import violations found")
+			os.Exit(1)
+		}
+
+		log.Println("no import violations")
+	case "coverage":
+		if flag.Arg(1) != "import" || flag.NArg() < 4 {
+			log.Fatal("usage: devex [-commit {{hash}}] coverage import {{project slug}} {{report}}")
+		}
+
+		report, err := filepath.Abs(flag.Arg(3))
+		if err != nil {
+			log.Fatal("report path ", err)
+		}
+
+		revision, err := datacollector.ImportCoverage(context.TODO(), data, flag.Arg(2), report, *commit)
+		if err != nil {
+			log.Fatal("coverage import error ", err)
+		}
+
+		log.Println("coverage snapshot saved, revision", revision.ID)
 	case "check_style":
 		path := flag.Arg(2)
 
@@ -110,5 +253,29 @@ func main() {
 
 		log.Printf("parsing success \n")
 		os.Exit(0)
+	case "sarif", "lint":
+		if flag.NArg() < 3 {
+			log.Fatal("usage: devex ", command, " {{project slug}} {{report}}")
+		}
+
+		importReport := datacollector.ImportSarif
+		if command == "lint" {
+			importReport = datacollector.ImportLint
+		}
+
+		err := importReport(data, alias, flag.Arg(2))
+		if err != nil {
+			log.Fatal(command, " report error ", err)
+		}
+
+		log.Println("lint errors saved")
 	}
 }
+
+func splitFlag(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
@@ -1,6 +1,9 @@
 package project
 
 import (
+	"fmt"
+	"path"
+	"strings"
 	"time"
 )
 
@@ -12,45 +15,150 @@ type Project struct {
 	Alias      string
 	Language   string
 	FolderPath string
-	CreatedAt  time.Time
+	// Include and Exclude are files patterns in .gitignore syntax applied on each data collection
+	Include []string `gorm:"serializer:json"`
+	Exclude []string `gorm:"serializer:json"`
+	// Rules are layering rules checked on packages imports graph
+	Rules []ImportRule `gorm:"serializer:json"`
+	// CoverageReports are coverage report paths relative to project folder, reports are detected if empty
+	CoverageReports []string `gorm:"serializer:json"`
+	CreatedAt       time.Time
 	// Add git path for Hosted version
 }
 
-// TODO packages prioritizing based on business value
-// type Package struct {
-// 	ID        ID
-// 	ProjectID ID
-// 	Path      string
-// 	Priority  Priority
-// 	Present   bool
-// }
-//
-// type Priority string
-//
-// const (
-// 	Regular    Priority = ""
-// 	Vital               = "vital"
-// 	Money               = "money"
-// 	Critical            = "critical"
-// 	Deprecated          = "deprecated"
-// )
-
-// TODO next
-// Версия кода. При загрузке новых данных более свежего коммита, старые данные помечаются неактуальными
-// type Revision struct {
-// 	Hash string
-// }
+// Package is project folder with business value priority
+type Package struct {
+	ID       ID
+	Project  ID `gorm:"index"`
+	Path     string
+	Priority Priority
+	Present  bool
+}
+
+// Match reports whether package path or one of its parents matches glob pattern.
+func (p Package) Match(glob string) bool {
+	for dir := p.Path; ; dir = path.Dir(dir) {
+		if ok, _ := path.Match(glob, dir); ok {
+			return true
+		}
+
+		if !strings.Contains(dir, "/") {
+			return false
+		}
+	}
+}
+
+// ImportRule forbids packages matching Package glob to import packages matching Deny globs.
+// Globs match package path or one of its parents, e.g. "domain/*" matches "domain/user/events".
+type ImportRule struct {
+	Package string   `yaml:"package" json:"package"`
+	Deny    []string `yaml:"deny" json:"deny"`
+}
+
+// Denied returns deny glob matching import of package "to" from package "from", it is empty if import is allowed
+func (r ImportRule) Denied(from, to string) string {
+	if !(Package{Path: from}).Match(r.Package) {
+		return ""
+	}
+
+	for _, glob := range r.Deny {
+		if (Package{Path: to}).Match(glob) {
+			return glob
+		}
+	}
+
+	return ""
+}
+
+// Priority is package business value
+type Priority string
+
+const (
+	Regular    Priority = ""
+	Vital      Priority = "vital"
+	Money      Priority = "money"
+	Critical   Priority = "critical"
+	Deprecated Priority = "deprecated"
+)
+
+var Priorities = []Priority{Vital, Money, Critical, Deprecated}
+
+// ParsePriority returns known priority. "regular" and empty string are Regular priority.
+func ParsePriority(s string) (Priority, error) {
+	if s == "" || s == "regular" {
+		return Regular, nil
+	}
+
+	for _, p := range Priorities {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+
+	return Regular, fmt.Errorf("unknown priority %q, expected one of %v or regular", s, Priorities)
+}
+
+// Revision is code version of data collection run.
+// File contains data of the latest revision, FileSnapshot, Coverage and LintError keep older revisions data.
+type Revision struct {
+	ID        ID
+	Project   ID     `gorm:"index"`
+	Hash      string // HEAD commit hash, empty for not git projects
+	CreatedAt time.Time
+	// CoverageOnly revision is imported coverage report snapshot without files data
+	CoverageOnly bool `gorm:"default:false"`
+}
 
 type File struct {
-	ID      ID
-	Project ID
-	Package string
-	Name    string
-	Lines   uint32
-	Symbols uint32
-	Tags    map[string]uint32 `gorm:"serializer:json"` // experiment with tags: nolint,billing,money,order
-	Imports []string          `gorm:"serializer:json"`
-	Present bool
+	ID       ID
+	Project  ID
+	Revision ID // latest revision file was present in
+	Package  string
+	Name     string
+	Language string // detected by extension, shebang and .gitattributes linguist-language
+	Lines    uint32
+	// CodeLines, CommentLines and BlankLines are counted by language comments syntax
+	CodeLines    uint32
+	CommentLines uint32
+	BlankLines   uint32
+	// PackageName and functions and types counts are parsed from Go files only
+	PackageName       string
+	Functions         uint32
+	ExportedFunctions uint32
+	ExportedTypes     uint32
+	Types             uint32
+	Interfaces        uint32
+	FunctionLines     uint32 // functions lines sum
+	MaxFunctionLines  uint32
+	// Complexity and CognitiveComplexity are file functions totals, other languages than Go are approximated by tokens
+	Complexity          uint32
+	CognitiveComplexity uint32
+	Symbols             uint32
+	Tags                map[string]uint32 `gorm:"serializer:json"` // experiment with tags: nolint,billing,money,order
+	Imports             []string          `gorm:"serializer:json"`
+	Present             bool
+}
+
+// FileSnapshot is file data on revision
+type FileSnapshot struct {
+	Revision  ID `gorm:"index"`
+	File      ID
+	Lines     uint32
+	CodeLines uint32
+	Symbols   uint32
+	Tags      map[string]uint32 `gorm:"serializer:json"`
+}
+
+// Function is file function complexity of the latest file revision
+type Function struct {
+	ID   ID
+	File ID `gorm:"index"`
+	// Name column differs from files name, so files filters can be used in functions queries
+	Name       string `gorm:"column:function_name"`
+	Line       uint32
+	Lines      uint32
+	Complexity uint32
+	Cognitive  uint32
 }
 
 type GitCommit struct {
@@ -61,6 +169,20 @@ type GitCommit struct {
 	Time    time.Time
 }
 
+// AuthorAlias merges author into canonical one in dashboard data
+type AuthorAlias struct {
+	ID        ID
+	Author    string `gorm:"uniqueIndex"`
+	Canonical string
+}
+
+// TeamMember maps canonical author email to team
+type TeamMember struct {
+	ID     ID
+	Team   string `gorm:"index"`
+	Author string `gorm:"uniqueIndex"`
+}
+
 type GitChange struct {
 	ID          ID
 	File        ID
@@ -77,28 +199,43 @@ type Import struct {
 
 type Coverage struct {
 	File           ID
-	Percent        uint8
+	Revision       ID    `gorm:"index"`
+	Percent        uint8 // covered lines percent
 	UncoveredCount uint32
 	UncoveredLines []uint32 `gorm:"serializer:json"`
+	// Statements and CoveredStatements are counted by Go coverage profile only
+	Statements        uint32
+	CoveredStatements uint32
+	// UncoveredRanges are sequential uncovered lines as [from, to] pairs
+	UncoveredRanges [][2]uint32 `gorm:"serializer:json"`
 }
 
-// TODO future UI
 // DataFetchJob contains project data collection job state
-// type DataFetchJob struct {
-// 	ProjectID  ID
-// 	DataSource string
-// 	CreatedAt  time.Time
-// 	FinishedAt *time.Time
-// }
+type DataFetchJob struct {
+	ID         ID
+	Project    ID     `gorm:"index"`
+	DataSource string // comma separated data sources
+	CreatedAt  time.Time
+	FinishedAt *time.Time
+	Error      string
+	Files      uint32 // files processed
+	Commits    uint32 // commits processed
+}
+
+func (j DataFetchJob) Finished() bool {
+	return j.FinishedAt != nil
+}
 
 type LintError struct {
 	Id         ID        `gorm:"primaryKey"`
 	FileId     ID        `gorm:"column:file_id;not null;index;comment:Foreign key to files"`
+	Revision   ID        `gorm:"column:revision;index;comment:Revision report was loaded for"`
 	CreatedAt  time.Time `gorm:"column:created_at;default:(DATETIME('now'));not null;comment:created at"`
 	FileColumn uint      `gorm:"column:file_column;not null;comment:Column with error"`
 	FileLine   uint      `gorm:"column:file_line;not null;comment:Row with error"`
 	Message    string    `gorm:"column:message;type:text;not null;comment:Error message"`
-	Severity   string    `gorm:"column:message;type:varchar(155);not null;comment:Severity error"`
-	Source     string    `gorm:"column:message;type:varchar(155);not null;comment:What source found error"`
+	Severity   string    `gorm:"column:severity;type:varchar(155);not null;default:'';comment:Severity error"`
+	Source     string    `gorm:"column:source;type:varchar(155);not null;default:'';comment:What source found error"`
+	Rule       string    `gorm:"column:rule;type:varchar(255);not null;default:'';comment:Rule id of SARIF report"`
 	File       *File     `gorm:"foreignKey:FileId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
@@ -1,7 +1,6 @@
 package slices
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -64,34 +63,6 @@ func Index[T any, K comparable](list []T, key func(T) K) map[K]T {
 	return m
 }
 
-func SQLFilter(column, s string) string {
-	return strings.Join(
-		Map(strings.Split(s, ";"),
-			func(mainPart string) string {
-				parts := strings.Split(mainPart, ",")
-
-				parts = Map(parts, func(smallPart string) string {
-					sql := column
-
-					if strings.HasPrefix(smallPart, "!") {
-						smallPart = strings.TrimPrefix(smallPart, "!")
-						sql += " not"
-					}
-
-					return sql + " like '%" + smallPart + "%'"
-				})
-
-				sql := strings.Join(parts, " or ")
-
-				if len(parts) <= 1 {
-					return sql
-				}
-
-				return fmt.Sprintf("(%s)", sql)
-			},
-		), " and ")
-}
-
 func MultiTrimPrefix(s string, pp []string) (r string) {
 	r = s
 